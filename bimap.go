@@ -0,0 +1,137 @@
+package ordmap
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// ErrDuplicateValue is returned when setting a value on an OrdBiMap that is already mapped to a different key.
+var ErrDuplicateValue = errors.New("ordmap: value is already mapped to a different key")
+
+// An OrdBiMap is a concurrency safe, bidirectional ordered map. Keys and values are both unique, and either one can be
+// used to look up the other in O(1). Ordering is shared between both directions and follows key insertion.
+type OrdBiMap[K comparable, V comparable] struct {
+	// fwd's lock guards reverse as well, so both directions always change together.
+	fwd     OrdMap[K, V]
+	reverse map[V]K
+}
+
+// NewBiMap returns a new, empty OrdBiMap.
+func NewBiMap[K comparable, V comparable]() OrdBiMap[K, V] {
+	return OrdBiMap[K, V]{
+		fwd:     New[K, V](0),
+		reverse: make(map[V]K),
+	}
+}
+
+// Entries returns the ordered slice of Entry structs which can be iterated on.
+func (bm *OrdBiMap[K, V]) Entries() []Entry[K, V] {
+	return bm.fwd.Entries()
+}
+
+// All returns an iterator over the map's key/value pairs in order.
+func (bm *OrdBiMap[K, V]) All() iter.Seq2[K, V] {
+	return bm.fwd.All()
+}
+
+// AllCtx returns an iterator over the map's key/value pairs in order. Iteration stops as soon as ctx is cancelled. The
+// read lock is held until iteration finishes or stops, so the loop body must not mutate the same map.
+func (bm *OrdBiMap[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return bm.fwd.AllCtx(ctx)
+}
+
+// Get returns the value mapped to key.
+func (bm *OrdBiMap[K, V]) Get(key K) (V, bool) {
+	return bm.fwd.Get(key)
+}
+
+// GetKey returns the key mapped to val.
+func (bm *OrdBiMap[K, V]) GetKey(val V) (K, bool) {
+	bm.fwd.m.RLock()
+	defer bm.fwd.m.RUnlock()
+	key, ok := bm.reverse[val]
+	return key, ok
+}
+
+// Has reports whether key is present.
+func (bm *OrdBiMap[K, V]) Has(key K) bool {
+	return bm.fwd.Has(key)
+}
+
+// HasValue reports whether val is mapped to any key.
+func (bm *OrdBiMap[K, V]) HasValue(val V) bool {
+	_, ok := bm.GetKey(val)
+	return ok
+}
+
+// Index returns the ordered index associated with the given key.
+func (bm *OrdBiMap[K, V]) Index(key K) (int, bool) {
+	return bm.fwd.Index(key)
+}
+
+// Set maps key to val. If val is already mapped to a different key, nothing is changed and ErrDuplicateValue is
+// returned. Use ForceSet to resolve the conflict by removing the other key instead.
+func (bm *OrdBiMap[K, V]) Set(key K, val V) error {
+	bm.fwd.m.Lock()
+	defer bm.fwd.unlock()
+	if existing, ok := bm.reverse[val]; ok && existing != key {
+		return ErrDuplicateValue
+	}
+
+	bm.set(key, val)
+	return nil
+}
+
+// ForceSet maps key to val, first deleting any other key val was mapped to.
+func (bm *OrdBiMap[K, V]) ForceSet(key K, val V) {
+	bm.fwd.m.Lock()
+	defer bm.fwd.unlock()
+	if existing, ok := bm.reverse[val]; ok && existing != key {
+		bm.fwd.delete(existing)
+	}
+
+	bm.set(key, val)
+}
+
+// set stores the pair in both directions, dropping the reverse mapping for key's previous value. The write lock must
+// be held by the caller.
+func (bm *OrdBiMap[K, V]) set(key K, val V) {
+	if idx, ok := bm.fwd.lookup[key]; ok {
+		delete(bm.reverse, bm.fwd.data[idx].Value)
+	}
+
+	bm.fwd.set(Entry[K, V]{Key: key, Value: val})
+	bm.reverse[val] = key
+}
+
+// Delete removes key and its value.
+func (bm *OrdBiMap[K, V]) Delete(key K) {
+	bm.fwd.m.Lock()
+	defer bm.fwd.unlock()
+	idx, ok := bm.fwd.lookup[key]
+	if !ok {
+		return
+	}
+
+	delete(bm.reverse, bm.fwd.data[idx].Value)
+	bm.fwd.delete(key)
+}
+
+// DeleteValue removes val and the key mapped to it.
+func (bm *OrdBiMap[K, V]) DeleteValue(val V) {
+	bm.fwd.m.Lock()
+	defer bm.fwd.unlock()
+	key, ok := bm.reverse[val]
+	if !ok {
+		return
+	}
+
+	delete(bm.reverse, val)
+	bm.fwd.delete(key)
+}
+
+// Len returns the current length of the map.
+func (bm *OrdBiMap[K, V]) Len() int {
+	return bm.fwd.Len()
+}
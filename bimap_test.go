@@ -0,0 +1,47 @@
+package ordmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_BiMap(t *testing.T) {
+	bm := ordmap.NewBiMap[int, string]()
+	if err := bm.Set(1, "one"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bm.Set(2, "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bm.Set(3, "one"); !errors.Is(err, ordmap.ErrDuplicateValue) {
+		t.Fatalf("expected duplicate value error, got %v", err)
+	}
+
+	if key, ok := bm.GetKey("two"); !ok || key != 2 {
+		t.Fatalf("expected two to map back to 2, got %d", key)
+	}
+
+	// updating a key frees up its old value
+	if err := bm.Set(1, "uno"); err != nil || bm.HasValue("one") {
+		t.Fatalf("expected one to be released after remapping 1, got %v", err)
+	}
+
+	bm.ForceSet(3, "two")
+	if bm.Has(2) || bm.Len() != 2 {
+		t.Fatal("expected ForceSet to remove the key previously mapped to two")
+	}
+
+	bm.DeleteValue("uno")
+	if bm.Has(1) || bm.Len() != 1 {
+		t.Fatal("expected DeleteValue to remove 1")
+	}
+
+	entries := bm.Entries()
+	if entries[0].Key != 3 || entries[0].Value != "two" {
+		t.Fatalf("expected only 3=two to remain, got %v", entries)
+	}
+}
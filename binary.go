@@ -0,0 +1,173 @@
+package ordmap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidSnapshot is returned by ReadFrom when its input isn't a snapshot written by WriteTo.
+var ErrInvalidSnapshot = errors.New("ordmap: invalid snapshot")
+
+// snapshotMagic starts every snapshot, followed by snapshotVersion. Fields longer than maxFieldSize are treated as
+// corruption rather than allocated.
+const (
+	snapshotMagic   = "ordm"
+	snapshotVersion = 1
+	maxFieldSize    = 1 << 30
+)
+
+// WriteTo writes every entry of the OrdMap to w in a compact binary format that ReadFrom can load back. Keys and values
+// are encoded with the codecs set by WithKeyCodec and WithValueCodec, or a default chosen by type. The entries are
+// captured with Snapshot, so writers aren't held up while w is written to. Entries with a TTL are written without it.
+//
+// The format is the magic "ordm", a version byte, and the entry count as a uvarint, followed by every key and value in
+// order, each prefixed by its length as a uvarint.
+func (om *OrdMap[K, V]) WriteTo(w io.Writer) (int64, error) {
+	keyCodec, valueCodec := om.opts.codecs()
+	snap := om.Snapshot()
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	buf := append([]byte(snapshotMagic), snapshotVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(snap.data)))
+	if _, err := bw.Write(buf); err != nil {
+		return cw.n, err
+	}
+
+	for _, entry := range snap.data {
+		var err error
+		if buf, err = appendField(buf[:0], keyCodec, entry.Key); err != nil {
+			return cw.n, fmt.Errorf("encoding key: %w", err)
+		}
+
+		if buf, err = appendField(buf, valueCodec, entry.Value); err != nil {
+			return cw.n, fmt.Errorf("encoding value: %w", err)
+		}
+
+		if _, err := bw.Write(buf); err != nil {
+			return cw.n, err
+		}
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ReadFrom loads a snapshot written by WriteTo from r, setting each of its entries in order under a single lock. The
+// codecs have to match the ones the snapshot was written with. Input is buffered, so r may be read past the end of the
+// snapshot. On error, the entries read before it are kept.
+func (om *OrdMap[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	keyCodec, valueCodec := om.opts.codecs()
+	cr := &countingReader{r: bufio.NewReader(r)}
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(cr, header); err != nil {
+		return cr.n, unexpected(err)
+	}
+
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return cr.n, fmt.Errorf("%w: bad magic", ErrInvalidSnapshot)
+	}
+
+	if header[len(snapshotMagic)] != snapshotVersion {
+		return cr.n, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, header[len(snapshotMagic)])
+	}
+
+	count, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, unexpected(err)
+	}
+
+	om.m.Lock()
+	defer om.unlock()
+	var field []byte
+	for range count {
+		if field, err = readField(cr, field); err != nil {
+			return cr.n, err
+		}
+
+		key, err := keyCodec.Decode(field)
+		if err != nil {
+			return cr.n, fmt.Errorf("decoding key: %w", err)
+		}
+
+		if field, err = readField(cr, field); err != nil {
+			return cr.n, err
+		}
+
+		val, err := valueCodec.Decode(field)
+		if err != nil {
+			return cr.n, fmt.Errorf("decoding value: %w", err)
+		}
+
+		om.set(Entry[K, V]{Key: key, Value: val})
+	}
+
+	return cr.n, nil
+}
+
+// readField reads a length-prefixed field into buf, reusing its storage when it's large enough.
+func readField(r *countingReader, buf []byte) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return buf, unexpected(err)
+	}
+
+	if size > maxFieldSize {
+		return buf, fmt.Errorf("%w: field of %d bytes", ErrInvalidSnapshot, size)
+	}
+
+	if uint64(cap(buf)) < size {
+		buf = make([]byte, size)
+	}
+
+	buf = buf[:size]
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return buf, unexpected(err)
+	}
+
+	return buf, nil
+}
+
+// unexpected turns a clean EOF in the middle of a snapshot into io.ErrUnexpectedEOF.
+func unexpected(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// A countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// A countingReader counts the bytes read through it.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+func (cr *countingReader) ReadByte() (byte, error) {
+	b, err := cr.r.ReadByte()
+	if err == nil {
+		cr.n++
+	}
+
+	return b, err
+}
@@ -0,0 +1,117 @@
+package ordmap_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_WriteToReadFrom(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := range 100 {
+		om.Set(fmt.Sprintf("key %d", i), i-50)
+	}
+
+	om.Delete("key 10")
+	var buf bytes.Buffer
+	written, err := om.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error writing snapshot: %v", err)
+	}
+
+	if written != int64(buf.Len()) {
+		t.Fatalf("expected WriteTo to report %d bytes, got %d", buf.Len(), written)
+	}
+
+	loaded := ordmap.New[string, int](0)
+	read, err := loaded.ReadFrom(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error reading snapshot: %v", err)
+	}
+
+	if read != written {
+		t.Fatalf("expected ReadFrom to report %d bytes, got %d", written, read)
+	}
+
+	if got, want := fmt.Sprint(loaded.Entries()), fmt.Sprint(om.Entries()); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	truncated := ordmap.New[string, int](0)
+	_, err = truncated.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected a truncated snapshot to fail with io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	_, err = truncated.ReadFrom(bytes.NewReader([]byte("nope!")))
+	if !errors.Is(err, ordmap.ErrInvalidSnapshot) {
+		t.Fatalf("expected bad input to fail with ErrInvalidSnapshot, got %v", err)
+	}
+}
+
+type point struct {
+	X, Y int
+}
+
+// pointCodec packs points as a pair of varints
+type pointCodec struct{}
+
+func (pointCodec) Append(buf []byte, p point) ([]byte, error) {
+	buf, _ = ordmap.IntCodec[int]{}.Append(buf, p.X)
+	return ordmap.IntCodec[int]{}.Append(buf, p.Y)
+}
+
+func (pointCodec) Decode(data []byte) (point, error) {
+	if len(data) != 2 {
+		return point{}, errors.New("expected two bytes")
+	}
+
+	x, _ := ordmap.IntCodec[int]{}.Decode(data[:1])
+	y, _ := ordmap.IntCodec[int]{}.Decode(data[1:])
+	return point{X: x, Y: y}, nil
+}
+
+func Test_WriteToReadFromCodecs(t *testing.T) {
+	// without a codec, structs fall back to JSON
+	om := ordmap.New[uint64, point](0)
+	om.Set(1, point{X: 1, Y: -1})
+	om.Set(2, point{X: 2, Y: -2})
+	var buf bytes.Buffer
+	if _, err := om.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error writing snapshot: %v", err)
+	}
+
+	loaded := ordmap.New[uint64, point](0)
+	if _, err := loaded.ReadFrom(&buf); err != nil {
+		t.Fatalf("unexpected error reading snapshot: %v", err)
+	}
+
+	if got, want := fmt.Sprint(loaded.Entries()), fmt.Sprint(om.Entries()); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	custom := ordmap.New(0, ordmap.WithValueCodec[uint64, point](pointCodec{}))
+	custom.BulkSet(om.Entries()...)
+	buf.Reset()
+	if _, err := custom.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error writing snapshot: %v", err)
+	}
+
+	// header, count, and two entries of a 1 byte key and 2 byte value, each with a 1 byte length prefix
+	if buf.Len() != 5+1+2*(2+3) {
+		t.Fatalf("expected the custom codec to produce 16 bytes, got %d", buf.Len())
+	}
+
+	loaded = ordmap.New(0, ordmap.WithValueCodec[uint64, point](pointCodec{}))
+	if _, err := loaded.ReadFrom(&buf); err != nil {
+		t.Fatalf("unexpected error reading snapshot: %v", err)
+	}
+
+	if got, want := fmt.Sprint(loaded.Entries()), fmt.Sprint(om.Entries()); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
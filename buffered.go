@@ -0,0 +1,159 @@
+package ordmap
+
+import (
+	"sync"
+	"time"
+)
+
+// A Buffer accumulates Set and Delete operations destined for an OrdMap and applies them in a single locked batch,
+// amortizing lock overhead for high-frequency producers. Buffered operations are not visible through the underlying
+// OrdMap until they are flushed. A Buffer is safe for concurrent use.
+type Buffer[K comparable, V any] struct {
+	m    sync.Mutex
+	om   *OrdMap[K, V]
+	opts bufferOptions
+	ops  []Change[K, V]
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// A BufferOption configures the flush thresholds of a Buffer.
+type BufferOption func(*bufferOptions)
+
+// bufferOptions holds the configuration assembled from the BufferOptions passed to Buffered.
+type bufferOptions struct {
+	size     int
+	interval time.Duration
+}
+
+// WithFlushSize flushes a Buffer automatically whenever it holds n operations.
+func WithFlushSize(n int) BufferOption {
+	return func(o *bufferOptions) {
+		o.size = n
+	}
+}
+
+// WithFlushInterval flushes a Buffer automatically every interval from a background goroutine. Buffers using this
+// option must be closed with Close to stop the goroutine.
+func WithFlushInterval(interval time.Duration) BufferOption {
+	return func(o *bufferOptions) {
+		o.interval = interval
+	}
+}
+
+// Buffered returns a Buffer that batches writes to the OrdMap. Without any options a Buffer only applies its
+// operations when Flush or Close is called. WithFlushInterval panics for maps created with NewUnsafe, since the
+// background goroutine would flush to them concurrently.
+func (om *OrdMap[K, V]) Buffered(opts ...BufferOption) *Buffer[K, V] {
+	var o bufferOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.interval > 0 && om.opts.unsafe {
+		panic("ordmap: WithFlushInterval on an OrdMap created with NewUnsafe")
+	}
+
+	b := &Buffer[K, V]{
+		om:   om,
+		opts: o,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if o.interval > 0 {
+		go b.run()
+	} else {
+		close(b.done)
+	}
+
+	return b
+}
+
+// run flushes the Buffer on every tick until it is closed.
+func (b *Buffer[K, V]) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.opts.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.Flush()
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// Set buffers setting a key/value pair.
+func (b *Buffer[K, V]) Set(key K, val V) {
+	b.push(Change[K, V]{Op: OpSet, Key: key, Value: val})
+}
+
+// Delete buffers deleting a key.
+func (b *Buffer[K, V]) Delete(key K) {
+	b.push(Change[K, V]{Op: OpDelete, Key: key})
+}
+
+// Len returns the number of operations waiting to be flushed.
+func (b *Buffer[K, V]) Len() int {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return len(b.ops)
+}
+
+// push buffers a single operation and flushes if the size threshold has been reached.
+func (b *Buffer[K, V]) push(op Change[K, V]) {
+	b.m.Lock()
+	b.ops = append(b.ops, op)
+	if b.opts.size > 0 && len(b.ops) >= b.opts.size {
+		b.flush()
+		return
+	}
+
+	b.m.Unlock()
+}
+
+// Flush applies every buffered operation to the underlying OrdMap, in the order they were made, under a single
+// acquisition of its write lock.
+func (b *Buffer[K, V]) Flush() {
+	b.m.Lock()
+	b.flush()
+}
+
+// flush applies the buffered operations and releases the Buffer's lock, which must be held by the caller. The OrdMap's
+// lock is taken before the Buffer's is released, so concurrent flushes still apply their batches in order, but hooks
+// and eviction callbacks only run once both are released, so they can use the Buffer.
+func (b *Buffer[K, V]) flush() {
+	if len(b.ops) == 0 {
+		b.m.Unlock()
+		return
+	}
+
+	b.om.m.Lock()
+	for _, op := range b.ops {
+		switch op.Op {
+		case OpSet:
+			b.om.set(Entry[K, V]{Key: op.Key, Value: op.Value})
+		case OpDelete:
+			b.om.delete(op.Key)
+		}
+	}
+
+	clear(b.ops)
+	b.ops = b.ops[:0]
+	b.m.Unlock()
+	b.om.unlock()
+}
+
+// Close stops the background flusher, if there is one, and flushes any remaining operations. The Buffer can still be
+// used after Close, but will only flush on size or when Flush is called.
+func (b *Buffer[K, V]) Close() {
+	b.once.Do(func() {
+		close(b.stop)
+	})
+	<-b.done
+	b.Flush()
+}
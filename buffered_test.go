@@ -0,0 +1,81 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_BufferedFlush(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	buf := om.Buffered(ordmap.WithFlushSize(10))
+
+	for i := 0; i < 5; i++ {
+		buf.Set(fmt.Sprintf("key %d", i), i)
+	}
+	buf.Delete("key 0")
+
+	if om.Len() != 0 {
+		t.Fatalf("expected buffered writes to not be visible before a flush, got length %d", om.Len())
+	}
+
+	buf.Flush()
+	if om.Len() != 4 || om.Has("key 0") {
+		t.Fatalf("expected 4 entries after flushing, got %d", om.Len())
+	}
+
+	for i := 5; i < 15; i++ {
+		buf.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	if om.Len() != 14 || buf.Len() != 0 {
+		t.Fatalf("expected reaching the flush size to flush automatically, got length %d", om.Len())
+	}
+
+	buf.Close()
+}
+
+func Test_BufferedHooksUseBuffer(t *testing.T) {
+	var buf *ordmap.Buffer[string, int]
+	var pending []int
+	om := ordmap.New(0, ordmap.WithOnSet(func(string, int) {
+		pending = append(pending, buf.Len())
+	}))
+	buf = om.Buffered(ordmap.WithFlushSize(2))
+
+	// the hook runs during the flush triggered by the second Set, so it deadlocks if the Buffer is still locked
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf.Set("a", 1)
+		buf.Set("b", 2)
+		buf.Flush()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected hooks to run after the Buffer is unlocked")
+	}
+
+	if fmt.Sprint(pending) != "[0 0]" {
+		t.Fatalf("expected the hooks to see an empty Buffer, got %v", pending)
+	}
+}
+
+func Test_BufferedInterval(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	buf := om.Buffered(ordmap.WithFlushInterval(time.Millisecond))
+	defer buf.Close()
+
+	buf.Set("life", 42)
+	deadline := time.Now().Add(time.Second)
+	for !om.Has("life") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected background flusher to apply buffered writes")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,156 @@
+package ordmap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A Loader fetches the value for a key that is missing from a Cache.
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// A Cache is an ordered read-through cache layered on an OrdMap. Missing keys are fetched with a Loader, and
+// concurrent Gets for the same missing key share a single call to it. Entries can optionally expire after a TTL and be
+// bounded to a maximum size with least-recently-used eviction. Failed loads are not cached.
+type Cache[K comparable, V any] struct {
+	om   OrdMap[K, V]
+	load Loader[K, V]
+	opts cacheOptions
+
+	m     sync.Mutex
+	calls map[K]*call[V]
+}
+
+// A call is an in-flight load shared by every Get waiting on the same key.
+type call[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// A CacheOption configures optional behavior of a Cache at construction time.
+type CacheOption func(*cacheOptions)
+
+// cacheOptions holds the configuration assembled from the CacheOptions passed to NewCache.
+type cacheOptions struct {
+	ttl        time.Duration
+	maxEntries int
+}
+
+// WithCacheTTL expires cached entries once ttl has elapsed since they were loaded or set. Expired entries are reaped in
+// the background, so a Cache using this option must be closed with Close.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithCacheSize bounds a Cache to maxEntries, evicting the least recently used entry when it grows beyond that.
+func WithCacheSize(maxEntries int) CacheOption {
+	return func(o *cacheOptions) {
+		o.maxEntries = maxEntries
+	}
+}
+
+// NewCache returns a new Cache that fills misses using load.
+func NewCache[K comparable, V any](load Loader[K, V], opts ...CacheOption) *Cache[K, V] {
+	var o cacheOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &Cache[K, V]{
+		load:  load,
+		opts:  o,
+		calls: make(map[K]*call[V]),
+	}
+
+	if o.maxEntries > 0 {
+		c.om = NewLRU[K, V](o.maxEntries)
+	} else {
+		c.om = New[K, V](0)
+	}
+
+	if o.ttl > 0 {
+		c.om.StartReaper(o.ttl)
+	}
+
+	return c
+}
+
+// Get returns the cached value for key, loading it if it's missing or expired. Only one load per key is in flight at
+// a time; concurrent callers wait for its result, or until their own ctx is done. The load runs with the values of
+// the ctx of the caller that started it, but isn't cancelled along with it, so one caller giving up doesn't fail the
+// others.
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	if val, ok := c.om.Get(key); ok {
+		return val, nil
+	}
+
+	c.m.Lock()
+	cl, ok := c.calls[key]
+	if !ok {
+		// the value may have been stored by a load that finished while we were waiting on the lock
+		if val, ok := c.om.Get(key); ok {
+			c.m.Unlock()
+			return val, nil
+		}
+
+		cl = &call[V]{done: make(chan struct{})}
+		c.calls[key] = cl
+		go c.fill(context.WithoutCancel(ctx), key, cl)
+	}
+	c.m.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.val, cl.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// fill runs the loader for key and publishes the result to every waiter.
+func (c *Cache[K, V]) fill(ctx context.Context, key K, cl *call[V]) {
+	cl.val, cl.err = c.load(ctx, key)
+	if cl.err == nil {
+		c.Set(key, cl.val)
+	}
+
+	c.m.Lock()
+	delete(c.calls, key)
+	c.m.Unlock()
+	close(cl.done)
+}
+
+// Set stores a value in the Cache directly, without calling the loader.
+func (c *Cache[K, V]) Set(key K, val V) {
+	if c.opts.ttl > 0 {
+		c.om.SetWithTTL(key, val, c.opts.ttl)
+		return
+	}
+
+	c.om.Set(key, val)
+}
+
+// Invalidate removes key from the Cache so the next Get loads it again.
+func (c *Cache[K, V]) Invalidate(key K) {
+	c.om.Delete(key)
+}
+
+// Len returns the number of cached entries, including expired entries that haven't been reaped yet.
+func (c *Cache[K, V]) Len() int {
+	return c.om.Len()
+}
+
+// Entries returns the cached entries in order. For size bounded caches the order runs from least to most recently
+// used.
+func (c *Cache[K, V]) Entries() []Entry[K, V] {
+	return c.om.Entries()
+}
+
+// Close stops the background reaper used to expire entries. It's safe to call on a Cache without a TTL.
+func (c *Cache[K, V]) Close() {
+	c.om.StopReaper()
+}
@@ -0,0 +1,123 @@
+package ordmap_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_CacheSingleFlight(t *testing.T) {
+	var loads atomic.Int64
+	release := make(chan struct{})
+	cache := ordmap.NewCache(func(_ context.Context, key string) (int, error) {
+		loads.Add(1)
+		<-release
+		return len(key), nil
+	})
+	defer cache.Close()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := cache.Get(context.Background(), "life")
+			if err != nil || val != 4 {
+				t.Errorf("expected 4, got %d (%v)", val, err)
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if _, err := cache.Get(context.Background(), "life"); err != nil || loads.Load() != 1 {
+		t.Fatalf("expected concurrent and later gets to share a single load, got %d loads", loads.Load())
+	}
+}
+
+func Test_CacheErrorsAndEviction(t *testing.T) {
+	fail := errors.New("fail")
+	var loads atomic.Int64
+	cache := ordmap.NewCache(func(_ context.Context, key int) (string, error) {
+		loads.Add(1)
+		if key < 0 {
+			return "", fail
+		}
+		return fmt.Sprint(key), nil
+	}, ordmap.WithCacheSize(2), ordmap.WithCacheTTL(time.Hour))
+	defer cache.Close()
+
+	if _, err := cache.Get(context.Background(), -1); !errors.Is(err, fail) {
+		t.Fatalf("expected loader error, got %v", err)
+	}
+
+	if cache.Len() != 0 {
+		t.Fatal("expected failed loads to not be cached")
+	}
+
+	for _, key := range []int{1, 2, 1, 3} {
+		if val, err := cache.Get(context.Background(), key); err != nil || val != fmt.Sprint(key) {
+			t.Fatalf("expected %d, got %s (%v)", key, val, err)
+		}
+	}
+
+	entries := cache.Entries()
+	if len(entries) != 2 || entries[0].Key != 1 || entries[1].Key != 3 {
+		t.Fatalf("expected least recently used key 2 to be evicted, got %v", entries)
+	}
+
+	cache.Invalidate(1)
+	cache.Get(context.Background(), 1)
+	if loads.Load() != 5 {
+		t.Fatalf("expected 5 loads, got %d", loads.Load())
+	}
+}
+
+func Test_CacheFirstCallerCancels(t *testing.T) {
+	release := make(chan struct{})
+	cache := ordmap.NewCache(func(ctx context.Context, key string) (int, error) {
+		select {
+		case <-release:
+			return len(key), nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+	defer cache.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := cache.Get(ctx, "life")
+		first <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	second := make(chan int)
+	go func() {
+		val, err := cache.Get(context.Background(), "life")
+		if err != nil {
+			t.Errorf("expected the second caller to get the value, got %v", err)
+		}
+		second <- val
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the first caller to be cancelled, got %v", err)
+	}
+
+	close(release)
+	if val := <-second; val != 4 {
+		t.Fatalf("expected 4, got %d", val)
+	}
+}
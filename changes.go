@@ -0,0 +1,137 @@
+package ordmap
+
+import "iter"
+
+// An Op identifies the kind of mutation described by a Change.
+type Op uint8
+
+const (
+	// OpSet describes a key being inserted or having its value updated.
+	OpSet Op = iota + 1
+	// OpDelete describes a key being removed. The Change carries the value the key held before removal.
+	OpDelete
+	// OpMove describes a key being moved to follow Prev, or to the front when HasPrev is false. The Change carries the
+	// key's current value.
+	OpMove
+)
+
+// String returns a human readable name for the Op.
+func (op Op) String() string {
+	switch op {
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	case OpMove:
+		return "move"
+	default:
+		return "unknown"
+	}
+}
+
+// A Change describes a single mutation applied to an OrdMap. Version is the map's mutation counter immediately after
+// the change was applied.
+type Change[K comparable, V any] struct {
+	Version uint64
+	Op      Op
+	Key     K
+	Value   V
+	// Prev and HasPrev are only used by OpMove.
+	Prev    K
+	HasPrev bool
+}
+
+// WithChangeLog retains the most recent size changes so they can be replayed with ChangesSince. Without this option
+// an OrdMap still tracks its Version but does not remember individual changes.
+func WithChangeLog[K comparable, V any](size int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.changeLogSize = size
+	}
+}
+
+// Version returns the OrdMap's mutation counter. It starts at zero and is incremented once for every set, every
+// delete that removes a key, and every explicit move.
+func (om *OrdMap[K, V]) Version() uint64 {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	return om.version
+}
+
+// ChangesSince returns an iterator over the retained changes made after version, oldest first. Only as many changes as
+// were configured with WithChangeLog are retained. If the log no longer reaches back to version, the first Change
+// yielded will have a Version greater than version+1, which callers can treat as a signal to fall back to a full
+// read. The read lock is held until iteration finishes or stops, so the loop body must not mutate the same OrdMap.
+func (om *OrdMap[K, V]) ChangesSince(version uint64) iter.Seq[Change[K, V]] {
+	return func(yield func(Change[K, V]) bool) {
+		om.m.RLock()
+		defer om.m.RUnlock()
+		for change := range om.changes.all() {
+			if change.Version <= version {
+				continue
+			}
+
+			if !yield(change) {
+				return
+			}
+		}
+	}
+}
+
+// record bumps the mutation counter, appends the change to the change log stamped with the new version, notifies any
+// watchers, counts it, and queues it for the OnSet and OnDelete hooks. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) record(change Change[K, V]) {
+	om.version++
+	change.Version = om.version
+	om.changes.push(change)
+	om.watchers.notify(change)
+	om.metrics.change(change.Op)
+	if om.opts.onSet != nil && change.Op == OpSet || om.opts.onDelete != nil && change.Op == OpDelete {
+		om.pending = append(om.pending, change)
+	}
+}
+
+// A changeLog is a fixed size ring buffer of the most recent changes.
+type changeLog[K comparable, V any] struct {
+	buf  []Change[K, V]
+	head int
+	full bool
+}
+
+func newChangeLog[K comparable, V any](size int) changeLog[K, V] {
+	if size <= 0 {
+		return changeLog[K, V]{}
+	}
+
+	return changeLog[K, V]{buf: make([]Change[K, V], size)}
+}
+
+func (cl *changeLog[K, V]) push(change Change[K, V]) {
+	if len(cl.buf) == 0 {
+		return
+	}
+
+	cl.buf[cl.head] = change
+	cl.head = (cl.head + 1) % len(cl.buf)
+	if cl.head == 0 {
+		cl.full = true
+	}
+}
+
+// all yields the retained changes from oldest to newest.
+func (cl *changeLog[K, V]) all() iter.Seq[Change[K, V]] {
+	return func(yield func(Change[K, V]) bool) {
+		if cl.full {
+			for _, change := range cl.buf[cl.head:] {
+				if !yield(change) {
+					return
+				}
+			}
+		}
+
+		for _, change := range cl.buf[:cl.head] {
+			if !yield(change) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_ChangesSince(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithChangeLog[string, int](3))
+
+	om.Set("a", 1)
+	om.Set("b", 2)
+	since := om.Version()
+	om.Set("a", 3)
+	om.Delete("b")
+	om.Delete("missing")
+
+	if om.Version() != 4 {
+		t.Fatalf("expected version 4, got %d", om.Version())
+	}
+
+	var changes []ordmap.Change[string, int]
+	for change := range om.ChangesSince(since) {
+		changes = append(changes, change)
+	}
+
+	expected := []ordmap.Change[string, int]{
+		{Version: 3, Op: ordmap.OpSet, Key: "a", Value: 3},
+		{Version: 4, Op: ordmap.OpDelete, Key: "b", Value: 2},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d", len(expected), len(changes))
+	}
+
+	for idx := range expected {
+		if changes[idx] != expected[idx] {
+			t.Fatalf("expected change #%d to be %+v, got %+v", idx, expected[idx], changes[idx])
+		}
+	}
+
+	// the log only retains 3 changes, so the first change for version 1 has been dropped
+	for change := range om.ChangesSince(0) {
+		if change.Version != 2 {
+			t.Fatalf("expected oldest retained change to be version 2, got %d", change.Version)
+		}
+		break
+	}
+}
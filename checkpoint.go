@@ -0,0 +1,119 @@
+package ordmap
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCheckpointGap is returned by Restore when a delta doesn't start where the previous checkpoint ended.
+var ErrCheckpointGap = errors.New("ordmap: checkpoint doesn't follow the previous one")
+
+// A Checkpoint captures an OrdMap's state as of Version, either in full or as the changes made since an earlier
+// checkpoint. Its fields are exported so it can be encoded with any encoder that handles the key and value types.
+type Checkpoint[K comparable, V any] struct {
+	// Since is the version the checkpoint was requested relative to.
+	Since uint64
+	// Version is the OrdMap's version when the checkpoint was taken.
+	Version uint64
+	// Full is set when Changes rebuild the whole map from empty, rather than only covering the changes after Since.
+	Full bool
+	// Changes holds the changes to replay. A full checkpoint holds a set for every entry, in order.
+	Changes []Change[K, V]
+}
+
+// Checkpoint returns a full checkpoint of the OrdMap's current state.
+func (om *OrdMap[K, V]) Checkpoint() Checkpoint[K, V] {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	return om.fullCheckpoint(0)
+}
+
+// CheckpointSince returns a checkpoint holding only the changes made after version, which is usually the Version of
+// the previous checkpoint. If the change log configured with WithChangeLog no longer reaches back that far, or version
+// isn't one the OrdMap has had, a full checkpoint is returned instead. Moves made by access ordering aren't recorded as
+// changes, so deltas of access ordered maps only reproduce their contents, not their order.
+func (om *OrdMap[K, V]) CheckpointSince(version uint64) Checkpoint[K, V] {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	if version > om.version {
+		return om.fullCheckpoint(version)
+	}
+
+	checkpoint := Checkpoint[K, V]{Since: version, Version: om.version}
+	for change := range om.changes.all() {
+		if change.Version <= version {
+			continue
+		}
+
+		if len(checkpoint.Changes) == 0 && change.Version != version+1 {
+			return om.fullCheckpoint(version)
+		}
+
+		checkpoint.Changes = append(checkpoint.Changes, change)
+	}
+
+	if uint64(len(checkpoint.Changes)) != om.version-version {
+		return om.fullCheckpoint(version)
+	}
+
+	return checkpoint
+}
+
+// fullCheckpoint captures every visible entry. The read lock must be held by the caller.
+func (om *OrdMap[K, V]) fullCheckpoint(since uint64) Checkpoint[K, V] {
+	checkpoint := Checkpoint[K, V]{
+		Since:   since,
+		Version: om.version,
+		Full:    true,
+		Changes: make([]Change[K, V], 0, len(om.lookup)),
+	}
+
+	now := time.Now()
+	for idx, entry := range om.data {
+		if om.visible(idx, now) {
+			checkpoint.Changes = append(checkpoint.Changes,
+				Change[K, V]{Version: om.version, Op: OpSet, Key: entry.Key, Value: entry.Value})
+		}
+	}
+
+	return checkpoint
+}
+
+// Restore replaces the OrdMap's contents with the state captured by base, which must be a full checkpoint, followed by
+// each of deltas in turn, all under a single lock. Each delta must either be full or start at the Version of the
+// checkpoint before it, otherwise ErrCheckpointGap is returned and the OrdMap is left as of the last checkpoint that
+// could be applied.
+func (om *OrdMap[K, V]) Restore(base Checkpoint[K, V], deltas ...Checkpoint[K, V]) error {
+	if !base.Full {
+		return fmt.Errorf("%w: base isn't a full checkpoint", ErrCheckpointGap)
+	}
+
+	om.m.Lock()
+	defer om.unlock()
+	var version uint64
+	for _, checkpoint := range append([]Checkpoint[K, V]{base}, deltas...) {
+		if checkpoint.Full {
+			// deleting can trigger a sweep that moves entries around, so the keys are gathered up front
+			keys := make([]K, 0, len(om.lookup))
+			for key := range om.lookup {
+				keys = append(keys, key)
+			}
+
+			for _, key := range keys {
+				om.delete(key)
+			}
+		} else if checkpoint.Since != version {
+			return fmt.Errorf("%w: expected a delta since version %d, got one since %d", ErrCheckpointGap, version,
+				checkpoint.Since)
+		}
+
+		for _, change := range checkpoint.Changes {
+			om.apply(change)
+		}
+
+		version = checkpoint.Version
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package ordmap_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Checkpoint(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithChangeLog[string, int](3))
+	om.Set("a", 1)
+	om.Set("b", 2)
+	base := om.Checkpoint()
+
+	om.Set("c", 3)
+	om.Delete("a")
+	first := om.CheckpointSince(base.Version)
+	if first.Full || len(first.Changes) != 2 {
+		t.Fatalf("expected a delta of 2 changes, got full=%t with %d changes", first.Full, len(first.Changes))
+	}
+
+	om.MoveToFront("c")
+	om.Set("b", 20)
+	second := om.CheckpointSince(first.Version)
+
+	follower := ordmap.New[string, int](0)
+	follower.Set("stale", 0)
+	if err := follower.Restore(base, first, second); err != nil {
+		t.Fatalf("unexpected error restoring: %v", err)
+	}
+
+	if got, want := fmt.Sprint(follower.Entries()), fmt.Sprint(om.Entries()); got != want {
+		t.Fatalf("expected restoring to produce %s, got %s", want, got)
+	}
+
+	if err := follower.Restore(base, second); !errors.Is(err, ordmap.ErrCheckpointGap) {
+		t.Fatalf("expected skipping a delta to fail with ErrCheckpointGap, got %v", err)
+	}
+
+	// the change log only holds 3 changes, so a checkpoint since the base has to fall back to a full one
+	if full := om.CheckpointSince(base.Version); !full.Full || len(full.Changes) != om.Len() {
+		t.Fatalf("expected a full checkpoint of %d entries, got full=%t with %d changes", om.Len(), full.Full,
+			len(full.Changes))
+	}
+
+	if empty := om.CheckpointSince(om.Version()); empty.Full || len(empty.Changes) != 0 {
+		t.Fatal("expected an empty delta when nothing changed")
+	}
+}
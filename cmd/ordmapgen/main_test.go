@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// program exercises a generated map and panics if it misbehaves.
+const program = `package main
+
+import "fmt"
+
+func main() {
+	om := NewCounts(0)
+	for i := range 10 {
+		om.Set(fmt.Sprint(i), i)
+	}
+
+	for i := range 8 {
+		om.Delete(fmt.Sprint(i))
+	}
+
+	om.Set("8", 80)
+	if idx, _ := om.Index("9"); idx != 1 || om.Len() != 2 {
+		panic(fmt.Sprint("unexpected index or length: ", idx, om.Len()))
+	}
+
+	if got := fmt.Sprint(om.Entries()); got != "[{8 80} {9 9}]" {
+		panic("unexpected entries: " + got)
+	}
+}
+`
+
+func Test_Generate(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	for _, locked := range []bool{false, true} {
+		src, err := generate(config{Package: "main", Type: "Counts", Key: "string", Value: "int", Locked: locked})
+		if err != nil {
+			t.Fatalf("unexpected error generating: %v", err)
+		}
+
+		if strings.Contains(string(src), "sync.RWMutex") != locked {
+			t.Fatalf("expected locking only when asked for, locked=%t", locked)
+		}
+
+		dir := t.TempDir()
+		files := map[string]string{
+			"go.mod":    "module gentest\n\ngo 1.24\n",
+			"counts.go": string(src),
+			"main.go":   program,
+		}
+
+		for name, contents := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+				t.Fatalf("unexpected error writing %s: %v", name, err)
+			}
+		}
+
+		cmd := exec.Command("go", "run", ".")
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("generated map failed with locked=%t: %v\n%s", locked, err, out)
+		}
+	}
+}
+
+func Test_GenerateRequiresTypes(t *testing.T) {
+	if _, err := generate(config{Package: "main", Type: "Counts"}); err == nil {
+		t.Fatal("expected missing key and value types to fail")
+	}
+}
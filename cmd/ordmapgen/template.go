@@ -0,0 +1,187 @@
+package main
+
+// mapTemplate is the source of a generated map. Locking is spliced in only when requested, so unlocked maps carry no
+// trace of it.
+const mapTemplate = `// Code generated by ordmapgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"iter"
+{{- if .Locked}}
+	"sync"
+{{- end}}
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+// {{.Type}}Entry is a key/value pair stored in a {{.Type}}.
+type {{.Type}}Entry struct {
+	Key   {{.Key}}
+	Value {{.Value}}
+}
+
+// {{.Type}} is an ordered map from {{.Key}} to {{.Value}}.
+{{- if .Locked}} It is safe for concurrent use.{{else}} It is not safe for concurrent use.{{end}}
+type {{.Type}} struct {
+{{- if .Locked}}
+	m          sync.RWMutex
+{{- end}}
+	lookup     map[{{.Key}}]int
+	data       []{{.Type}}Entry
+	tombstones int
+}
+
+// New{{.Type}} returns an empty {{.Type}} with room for initialSize entries.
+func New{{.Type}}(initialSize int) *{{.Type}} {
+	return &{{.Type}}{
+		lookup: make(map[{{.Key}}]int, initialSize),
+		data:   make([]{{.Type}}Entry, 0, initialSize),
+	}
+}
+
+// Get returns the value stored at key and whether it was found.
+func (om *{{.Type}}) Get(key {{.Key}}) ({{.Value}}, bool) {
+{{- if .Locked}}
+	om.m.RLock()
+	defer om.m.RUnlock()
+{{- end}}
+	if idx, ok := om.lookup[key]; ok {
+		return om.data[idx].Value, true
+	}
+
+	var zero {{.Value}}
+	return zero, false
+}
+
+// Has returns whether key is present.
+func (om *{{.Type}}) Has(key {{.Key}}) bool {
+{{- if .Locked}}
+	om.m.RLock()
+	defer om.m.RUnlock()
+{{- end}}
+	_, ok := om.lookup[key]
+	return ok
+}
+
+// Set stores val at key. New keys are appended to the end of the ordering, and existing keys keep their position.
+func (om *{{.Type}}) Set(key {{.Key}}, val {{.Value}}) {
+{{- if .Locked}}
+	om.m.Lock()
+	defer om.m.Unlock()
+{{- end}}
+	if idx, ok := om.lookup[key]; ok {
+		om.data[idx].Value = val
+		return
+	}
+
+	om.lookup[key] = len(om.data)
+	om.data = append(om.data, {{.Type}}Entry{Key: key, Value: val})
+}
+
+// Delete removes key, leaving a tombstone in its slot that's swept once tombstones make up more than half of them.
+func (om *{{.Type}}) Delete(key {{.Key}}) {
+{{- if .Locked}}
+	om.m.Lock()
+	defer om.m.Unlock()
+{{- end}}
+	idx, ok := om.lookup[key]
+	if !ok {
+		return
+	}
+
+	delete(om.lookup, key)
+	var zero {{.Value}}
+	om.data[idx].Value = zero
+	om.tombstones++
+	if om.tombstones*2 > len(om.data) {
+		om.sweep()
+	}
+}
+
+// sweep compacts tombstones out of data and reindexes the entries that moved.
+func (om *{{.Type}}) sweep() {
+	live := 0
+	for idx, entry := range om.data {
+		if !om.live(idx) {
+			continue
+		}
+
+		om.data[live] = entry
+		om.lookup[entry.Key] = live
+		live++
+	}
+
+	clear(om.data[live:])
+	om.data = om.data[:live]
+	om.tombstones = 0
+}
+
+// live reports whether the slot at idx holds a live entry rather than a tombstone.
+func (om *{{.Type}}) live(idx int) bool {
+	if om.tombstones == 0 {
+		return true
+	}
+
+	lookupIdx, ok := om.lookup[om.data[idx].Key]
+	return ok && lookupIdx == idx
+}
+
+// Len returns the number of entries.
+func (om *{{.Type}}) Len() int {
+{{- if .Locked}}
+	om.m.RLock()
+	defer om.m.RUnlock()
+{{- end}}
+	return len(om.lookup)
+}
+
+// Index returns the position of key in the ordering.
+func (om *{{.Type}}) Index(key {{.Key}}) (int, bool) {
+{{- if .Locked}}
+	om.m.Lock()
+	defer om.m.Unlock()
+{{- end}}
+	if om.tombstones > 0 {
+		om.sweep()
+	}
+
+	idx, ok := om.lookup[key]
+	return idx, ok
+}
+
+// All returns an iterator over the key/value pairs in order. The loop body must not modify the map.
+{{- if .Locked}}
+// The read lock is held until iteration finishes or stops.
+{{- end}}
+func (om *{{.Type}}) All() iter.Seq2[{{.Key}}, {{.Value}}] {
+	return func(yield func({{.Key}}, {{.Value}}) bool) {
+{{- if .Locked}}
+		om.m.RLock()
+		defer om.m.RUnlock()
+{{- end}}
+		for idx, entry := range om.data {
+			if om.live(idx) && !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Entries returns a newly allocated, ordered slice of the entries.
+func (om *{{.Type}}) Entries() []{{.Type}}Entry {
+{{- if .Locked}}
+	om.m.RLock()
+	defer om.m.RUnlock()
+{{- end}}
+	entries := make([]{{.Type}}Entry, 0, len(om.lookup))
+	for idx, entry := range om.data {
+		if om.live(idx) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+`
@@ -0,0 +1,141 @@
+package ordmap
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// A Codec converts keys or values of type T to and from bytes for WriteTo and ReadFrom, the WAL, and the mmap layout.
+type Codec[T any] interface {
+	// Append appends the encoding of val to buf and returns the extended buffer.
+	Append(buf []byte, val T) ([]byte, error)
+	// Decode decodes a value from data, which holds exactly what one call to Append appended. It must not retain data.
+	Decode(data []byte) (T, error)
+}
+
+// StringCodec encodes strings as their raw bytes.
+type StringCodec struct{}
+
+func (StringCodec) Append(buf []byte, val string) ([]byte, error) {
+	return append(buf, val...), nil
+}
+
+func (StringCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
+
+// BytesCodec encodes byte slices as themselves.
+type BytesCodec struct{}
+
+func (BytesCodec) Append(buf []byte, val []byte) ([]byte, error) {
+	return append(buf, val...), nil
+}
+
+func (BytesCodec) Decode(data []byte) ([]byte, error) {
+	return append([]byte(nil), data...), nil
+}
+
+// Integer is the set of integer types IntCodec supports.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// IntCodec encodes integers as zig-zag varints, so small values of any sign take few bytes.
+type IntCodec[T Integer] struct{}
+
+func (IntCodec[T]) Append(buf []byte, val T) ([]byte, error) {
+	return binary.AppendVarint(buf, int64(val)), nil
+}
+
+func (IntCodec[T]) Decode(data []byte) (T, error) {
+	val, n := binary.Varint(data)
+	if n <= 0 || n != len(data) {
+		return 0, fmt.Errorf("%w: malformed varint", ErrInvalidSnapshot)
+	}
+
+	return T(val), nil
+}
+
+// JSONCodec encodes values of any type with encoding/json. It's the fallback for types without a dedicated codec, and
+// is far slower than one.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Append(buf []byte, val T) ([]byte, error) {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return buf, err
+	}
+
+	return append(buf, data...), nil
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var val T
+	err := json.Unmarshal(data, &val)
+	return val, err
+}
+
+// WithKeyCodec sets the codec used for keys by WriteTo, ReadFrom, a WAL, and the mmap layout.
+func WithKeyCodec[K comparable, V any](codec Codec[K]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.keyCodec = codec
+	}
+}
+
+// WithValueCodec sets the codec used for values by WriteTo, ReadFrom, a WAL, and the mmap layout.
+func WithValueCodec[K comparable, V any](codec Codec[V]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.valueCodec = codec
+	}
+}
+
+// defaultCodec picks a codec for T when none was configured: a dedicated one for strings, byte slices, and the
+// built-in integer types, and JSONCodec for everything else.
+func defaultCodec[T any]() Codec[T] {
+	var codec any
+	switch any(*new(T)).(type) {
+	case string:
+		codec = StringCodec{}
+	case []byte:
+		codec = BytesCodec{}
+	case int:
+		codec = IntCodec[int]{}
+	case int8:
+		codec = IntCodec[int8]{}
+	case int16:
+		codec = IntCodec[int16]{}
+	case int32:
+		codec = IntCodec[int32]{}
+	case int64:
+		codec = IntCodec[int64]{}
+	case uint:
+		codec = IntCodec[uint]{}
+	case uint8:
+		codec = IntCodec[uint8]{}
+	case uint16:
+		codec = IntCodec[uint16]{}
+	case uint32:
+		codec = IntCodec[uint32]{}
+	case uint64:
+		codec = IntCodec[uint64]{}
+	default:
+		return JSONCodec[T]{}
+	}
+
+	return codec.(Codec[T])
+}
+
+// codecs returns the codecs used for keys and values, falling back to defaults for any that weren't configured.
+func (o options[K, V]) codecs() (Codec[K], Codec[V]) {
+	keyCodec, valueCodec := o.keyCodec, o.valueCodec
+	if keyCodec == nil {
+		keyCodec = defaultCodec[K]()
+	}
+
+	if valueCodec == nil {
+		valueCodec = defaultCodec[V]()
+	}
+
+	return keyCodec, valueCodec
+}
@@ -0,0 +1,155 @@
+package ordmap
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// A cowState is an immutable snapshot of a CopyOnWrite map's contents.
+type cowState[K comparable, V any] struct {
+	lookup map[K]int
+	data   []Entry[K, V]
+}
+
+// A CopyOnWrite is an ordered map optimized for read-mostly workloads. Its contents are published as an immutable
+// snapshot behind an atomic pointer, so Get, Has, Index, Len, and iteration never take a lock. Every write copies the
+// current snapshot, applies the change, and publishes the result, which makes writes O(n) and serializes writers
+// behind a mutex. BulkSet should be strongly preferred over repeated calls to Set.
+type CopyOnWrite[K comparable, V any] struct {
+	m     sync.Mutex
+	state atomic.Pointer[cowState[K, V]]
+}
+
+// NewCopyOnWrite returns a new, empty CopyOnWrite map.
+func NewCopyOnWrite[K comparable, V any]() CopyOnWrite[K, V] {
+	return CopyOnWrite[K, V]{}
+}
+
+// load returns the currently published snapshot.
+func (cm *CopyOnWrite[K, V]) load() *cowState[K, V] {
+	if state := cm.state.Load(); state != nil {
+		return state
+	}
+
+	return &cowState[K, V]{}
+}
+
+// Entries returns a newly allocated slice of the map's entries in order.
+func (cm *CopyOnWrite[K, V]) Entries() []Entry[K, V] {
+	return slices.Clone(cm.load().data)
+}
+
+// All returns an iterator over the map's key/value pairs in order. It is equivalent to AllCtx with a context that is
+// never cancelled.
+func (cm *CopyOnWrite[K, V]) All() iter.Seq2[K, V] {
+	return cm.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the snapshot that was current when iteration started. Iteration stops as soon as ctx
+// is cancelled. No locks are held, so the loop body may freely mutate the map.
+func (cm *CopyOnWrite[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, entry := range cm.load().data {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Get implements a lock-free map lookup.
+func (cm *CopyOnWrite[K, V]) Get(key K) (V, bool) {
+	state := cm.load()
+	idx, ok := state.lookup[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return state.data[idx].Value, true
+}
+
+// Index returns the ordered index associated with the given key.
+func (cm *CopyOnWrite[K, V]) Index(key K) (int, bool) {
+	idx, ok := cm.load().lookup[key]
+	return idx, ok
+}
+
+// Set a key/value pair within the map. This copies the entire map and is O(n).
+func (cm *CopyOnWrite[K, V]) Set(key K, val V) {
+	cm.BulkSet(Entry[K, V]{Key: key, Value: val})
+}
+
+// BulkSet sets many entries while copying the map only once. In the case of duplicated keys, earlier values in the
+// list will be overwritten.
+func (cm *CopyOnWrite[K, V]) BulkSet(entries ...Entry[K, V]) {
+	cm.m.Lock()
+	defer cm.m.Unlock()
+	state := cm.load()
+	next := &cowState[K, V]{
+		lookup: make(map[K]int, len(state.lookup)+len(entries)),
+		data:   make([]Entry[K, V], len(state.data), len(state.data)+len(entries)),
+	}
+
+	copy(next.data, state.data)
+	for key, idx := range state.lookup {
+		next.lookup[key] = idx
+	}
+
+	for _, entry := range entries {
+		if idx, ok := next.lookup[entry.Key]; ok {
+			next.data[idx] = entry
+			continue
+		}
+
+		next.lookup[entry.Key] = len(next.data)
+		next.data = append(next.data, entry)
+	}
+
+	cm.state.Store(next)
+}
+
+// Has works the same as Get but does not return the value. It's included for convenience.
+func (cm *CopyOnWrite[K, V]) Has(key K) bool {
+	_, ok := cm.load().lookup[key]
+	return ok
+}
+
+// Delete a key from the map. This copies the entire map and is O(n).
+func (cm *CopyOnWrite[K, V]) Delete(key K) {
+	cm.m.Lock()
+	defer cm.m.Unlock()
+	state := cm.load()
+	del, ok := state.lookup[key]
+	if !ok {
+		return
+	}
+
+	next := &cowState[K, V]{
+		lookup: make(map[K]int, len(state.lookup)-1),
+		data:   make([]Entry[K, V], 0, len(state.data)-1),
+	}
+
+	for idx, entry := range state.data {
+		if idx == del {
+			continue
+		}
+
+		next.lookup[entry.Key] = len(next.data)
+		next.data = append(next.data, entry)
+	}
+
+	cm.state.Store(next)
+}
+
+// Len returns the current length of the map.
+func (cm *CopyOnWrite[K, V]) Len() int {
+	return len(cm.load().data)
+}
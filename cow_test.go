@@ -0,0 +1,86 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_CopyOnWriteSnapshotIsolation(t *testing.T) {
+	cm := ordmap.NewCopyOnWrite[string, int]()
+	for i := 0; i < 10; i++ {
+		cm.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	before := cm.Entries()
+	cm.Delete("key 3")
+	cm.Set("key 0", 100)
+
+	if len(before) != 10 || before[0].Value != 0 {
+		t.Fatal("expected previously returned entries to be unaffected by later writes")
+	}
+
+	if cm.Len() != 9 || cm.Has("key 3") {
+		t.Fatalf("expected key 3 to be deleted, length is %d", cm.Len())
+	}
+
+	if idx, ok := cm.Index("key 4"); !ok || idx != 3 {
+		t.Fatalf("expected key 4 to shift to index 3, got %d", idx)
+	}
+
+	// mutating inside the loop is safe because iteration walks an immutable snapshot
+	count := 0
+	for key := range cm.All() {
+		cm.Delete(key)
+		count++
+	}
+
+	if count != 9 || cm.Len() != 0 {
+		t.Fatalf("expected to visit 9 entries and delete them all, visited %d with %d left", count, cm.Len())
+	}
+}
+
+func Test_CopyOnWriteEntriesCopies(t *testing.T) {
+	cm := ordmap.NewCopyOnWrite[string, int]()
+	cm.Set("a", 1)
+	cm.Set("b", 2)
+
+	entries := cm.Entries()
+	entries[0].Value = 100
+	if val, _ := cm.Get("a"); val != 1 {
+		t.Fatalf("expected modifying the returned entries to leave the map alone, got %d", val)
+	}
+
+	if got := fmt.Sprint(cm.Entries()); got != "[{a 1} {b 2}]" {
+		t.Fatalf("expected the published snapshot to be unchanged, got %s", got)
+	}
+}
+
+func Test_CopyOnWriteConcurrentAccess(t *testing.T) {
+	cm := ordmap.NewCopyOnWrite[string, int]()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(idx int) {
+			for j := 0; j < 100; j++ {
+				cm.Set(fmt.Sprintf("%d", j), idx*j)
+			}
+			wg.Done()
+		}(i)
+		go func() {
+			for j := 0; j < 100; j++ {
+				cm.Get(fmt.Sprintf("%d", j))
+				cm.Len()
+			}
+			wg.Done()
+		}()
+	}
+
+	wg.Wait()
+	if cm.Len() != 100 {
+		t.Fatalf("expected final map length to be 100, got %d", cm.Len())
+	}
+}
@@ -0,0 +1,26 @@
+package ordmap
+
+import "time"
+
+// WithDefault makes Get on a missing key call fn to create a default value, insert it, and return it, all under one
+// lock, like Python's defaultdict. Concurrent Gets for the same missing key only call fn once. fn runs while the write
+// lock is held, so it must not use the OrdMap. Has, Index, and iteration don't create defaults.
+func WithDefault[K comparable, V any](fn func(key K) V) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.defaultValue = fn
+	}
+}
+
+// getOrCreate returns the value of a normalized key, inserting a default value first if it's missing.
+func (om *OrdMap[K, V]) getOrCreate(key K) V {
+	om.m.Lock()
+	defer om.unlock()
+	// another Get may have created the value since the read lock was released
+	if idx, ok := om.lookup[key]; ok && !om.expired(key, time.Now()) {
+		return om.data[idx].Value
+	}
+
+	val := om.opts.defaultValue(key)
+	om.set(Entry[K, V]{Key: key, Value: val})
+	return val
+}
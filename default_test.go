@@ -0,0 +1,60 @@
+package ordmap_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_WithDefault(t *testing.T) {
+	calls := 0
+	om := ordmap.New(0, ordmap.WithDefault(func(key string) []string {
+		calls++
+		return []string{}
+	}))
+
+	for _, word := range strings.Fields("apple avocado banana blueberry cherry") {
+		group, _ := om.Get(word[:1])
+		om.Set(word[:1], append(group, word))
+	}
+
+	if got := om.String(); got != "ordmap[a:[apple avocado] b:[banana blueberry] c:[cherry]]" {
+		t.Fatalf("expected words grouped by first letter, got %s", got)
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected a default to be created once per group, got %d calls", calls)
+	}
+
+	if om.Has("d") {
+		t.Fatal("expected Has not to create a default")
+	}
+
+	counts := ordmap.New(0, ordmap.WithDefault(func(string) int { return 0 }))
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if val, ok := counts.Get("key"); !ok || val != 0 {
+				t.Errorf("expected a zero default, got %d, %t", val, ok)
+			}
+		}()
+	}
+
+	wg.Wait()
+	if counts.Len() != 1 {
+		t.Fatalf("expected a single default entry, got %d", counts.Len())
+	}
+}
+
+func Test_WithDefaultMetrics(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithDefault(func(string) int { return 0 }), ordmap.WithMetrics[string, int]())
+	om.Get("a")
+	om.Get("a")
+	if got := om.Metrics(); got.Hits != 1 || got.Misses != 1 {
+		t.Fatalf("expected creating a default to count as a miss and finding it later as a hit, got %+v", got)
+	}
+}
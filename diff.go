@@ -0,0 +1,128 @@
+package ordmap
+
+// Diff returns the changes that turn a into b: deletes for keys missing from b, sets for keys that are new or whose
+// values differ, and moves for keys whose relative order differs. Moves are kept to a minimum by leaving the longest
+// run of keys that are already in b's order where they are.
+//
+// Applying the result to a map holding the same entries as a leaves it equal to b, which makes Diff and Apply a simple
+// way to sync ordered state across processes. The Version of each Change is left at zero.
+func Diff[K, V comparable](a, b *OrdMap[K, V]) []Change[K, V] {
+	from, to := a.snapshot(), b.snapshot()
+	target := make(map[K]int, len(to))
+	for idx, entry := range to {
+		target[entry.Key] = idx
+	}
+
+	var changes []Change[K, V]
+	current := make(map[K]V, len(from))
+	// pos is where every key of b sits once the deletes and sets have been applied. Kept keys hold their relative
+	// order from a, and new keys are appended in b's order.
+	pos := make([]int, len(to))
+	next := 0
+	for _, entry := range from {
+		idx, ok := target[entry.Key]
+		if !ok {
+			changes = append(changes, Change[K, V]{Op: OpDelete, Key: entry.Key, Value: entry.Value})
+			continue
+		}
+
+		current[entry.Key] = entry.Value
+		pos[idx] = next
+		next++
+	}
+
+	for idx, entry := range to {
+		val, ok := current[entry.Key]
+		if !ok {
+			pos[idx] = next
+			next++
+		}
+
+		if !ok || val != entry.Value {
+			changes = append(changes, Change[K, V]{Op: OpSet, Key: entry.Key, Value: entry.Value})
+		}
+	}
+
+	stay := increasing(pos)
+	for idx, entry := range to {
+		if stay[idx] {
+			continue
+		}
+
+		change := Change[K, V]{Op: OpMove, Key: entry.Key, Value: entry.Value}
+		if idx > 0 {
+			change.Prev, change.HasPrev = to[idx-1].Key, true
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// increasing marks the members of a longest strictly increasing subsequence of seq.
+func increasing(seq []int) []bool {
+	// tails[n] is the index into seq of the smallest tail of any increasing subsequence of length n+1
+	tails := make([]int, 0, len(seq))
+	prev := make([]int, len(seq))
+	for idx, val := range seq {
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if seq[tails[mid]] < val {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+
+		prev[idx] = -1
+		if lo > 0 {
+			prev[idx] = tails[lo-1]
+		}
+
+		if lo == len(tails) {
+			tails = append(tails, idx)
+		} else {
+			tails[lo] = idx
+		}
+	}
+
+	marked := make([]bool, len(seq))
+	if len(tails) == 0 {
+		return marked
+	}
+
+	for idx := tails[len(tails)-1]; idx >= 0; idx = prev[idx] {
+		marked[idx] = true
+	}
+
+	return marked
+}
+
+// Apply replays changes, such as those returned by Diff or ChangesSince, in order and under a single lock. A move is
+// applied by placing its key right after Prev, or at the front when HasPrev is false. Moves of keys that aren't present
+// are ignored.
+func (om *OrdMap[K, V]) Apply(changes []Change[K, V]) {
+	om.m.Lock()
+	defer om.unlock()
+	for _, change := range changes {
+		om.apply(change)
+	}
+}
+
+// apply replays a single change. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) apply(change Change[K, V]) {
+	switch change.Op {
+	case OpSet:
+		om.set(Entry[K, V]{Key: change.Key, Value: change.Value})
+	case OpDelete:
+		om.delete(change.Key)
+	case OpMove:
+		if change.HasPrev {
+			om.moveNextTo(change.Key, change.Prev, true)
+		} else {
+			om.moveToFront(change.Key)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Diff(t *testing.T) {
+	a := ordmap.New[string, int](0)
+	b := ordmap.New[string, int](0)
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		a.Set(key, i)
+	}
+
+	for i, key := range []string{"e", "a", "c", "f", "b"} {
+		b.Set(key, i)
+	}
+
+	changes := ordmap.Diff(&a, &b)
+	var ops []string
+	for _, change := range changes {
+		ops = append(ops, fmt.Sprintf("%s %s", change.Op, change.Key))
+	}
+
+	// d is removed, c keeps its value, and only e and b need to move since a, c, and f are already in order once f is
+	// appended
+	expected := "[delete d set e set a set f set b move e move b]"
+	if got := fmt.Sprint(ops); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	a.Apply(changes)
+	if got, want := fmt.Sprint(a.Entries()), fmt.Sprint(b.Entries()); got != want {
+		t.Fatalf("expected applying the diff to produce %s, got %s", want, got)
+	}
+
+	if len(ordmap.Diff(&a, &b)) != 0 {
+		t.Fatal("expected no changes between equal maps")
+	}
+}
+
+func Test_DiffRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for range 100 {
+		a := ordmap.New[int, int](0)
+		b := ordmap.New[int, int](0)
+		for _, key := range rng.Perm(20)[:rng.Intn(20)] {
+			a.Set(key, rng.Intn(3))
+		}
+
+		for _, key := range rng.Perm(20)[:rng.Intn(20)] {
+			b.Set(key, rng.Intn(3))
+		}
+
+		a.Apply(ordmap.Diff(&a, &b))
+		if got, want := fmt.Sprint(a.Entries()), fmt.Sprint(b.Entries()); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	}
+}
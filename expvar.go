@@ -0,0 +1,31 @@
+package ordmap
+
+import "expvar"
+
+// Var returns an expvar.Var reporting the OrdMap's Stats along with its Version, which counts the sets, deletes, and
+// moves made so far, and its Metrics when they're enabled with WithMetrics. Every call to its String method takes the
+// read lock briefly.
+func (om *OrdMap[K, V]) Var() expvar.Var {
+	return expvar.Func(func() any {
+		om.m.RLock()
+		version := om.version
+		om.m.RUnlock()
+		v := struct {
+			Stats
+			Version uint64
+			Metrics *Metrics `json:",omitempty"`
+		}{Stats: om.Stats(), Version: version}
+		if om.metrics != nil {
+			metrics := om.Metrics()
+			v.Metrics = &metrics
+		}
+
+		return v
+	})
+}
+
+// Publish publishes the OrdMap's Var under name, so that it's served from the standard /debug/vars endpoint along with
+// every other expvar. Like expvar.Publish, it panics if name is already in use.
+func (om *OrdMap[K, V]) Publish(name string) {
+	expvar.Publish(name, om.Var())
+}
@@ -0,0 +1,58 @@
+package ordmap_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Publish(t *testing.T) {
+	om := ordmap.New[string, int](8)
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Delete("a")
+	om.Publish("ordmap_test")
+
+	var vars struct {
+		Len     int
+		Cap     int
+		Version uint64
+	}
+
+	if err := json.Unmarshal([]byte(expvar.Get("ordmap_test").String()), &vars); err != nil {
+		t.Fatalf("unexpected error decoding published var: %v", err)
+	}
+
+	if vars.Len != 1 || vars.Cap != 8 || vars.Version != 3 {
+		t.Fatalf("expected len 1, cap 8, and version 3, got %+v", vars)
+	}
+}
+
+func Test_PublishMetrics(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithMetrics[string, int]())
+	om.Set("a", 1)
+	om.Get("a")
+	om.Get("b")
+
+	var vars struct {
+		Len     int
+		Metrics *ordmap.Metrics
+	}
+
+	if err := json.Unmarshal([]byte(om.Var().String()), &vars); err != nil {
+		t.Fatalf("unexpected error decoding var: %v", err)
+	}
+
+	if vars.Metrics == nil || *vars.Metrics != om.Metrics() {
+		t.Fatalf("expected the var to report %+v, got %+v", om.Metrics(), vars.Metrics)
+	}
+
+	plain := ordmap.New[string, int](0)
+	vars.Metrics = nil
+	json.Unmarshal([]byte(plain.Var().String()), &vars)
+	if vars.Metrics != nil {
+		t.Fatal("expected no metrics without WithMetrics")
+	}
+}
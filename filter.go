@@ -0,0 +1,90 @@
+package ordmap
+
+import (
+	"hash/maphash"
+	"sync/atomic"
+)
+
+const (
+	// filterBitsPerKey and filterHashes give the key filter a false positive rate of roughly 1%.
+	filterBitsPerKey = 10
+	filterHashes     = 7
+	// minFilterCapacity is the smallest number of keys a key filter is sized for.
+	minFilterCapacity = 64
+)
+
+// WithKeyFilter maintains a Bloom filter of the OrdMap's keys alongside the lookup map, so Has can answer for keys that
+// are definitely absent without taking the lock. Keys that may be present, including the roughly 1% of absent keys the
+// filter can't rule out, still take the read lock. This costs about 20 bits per key and a little extra work on every
+// insert, and pays off for maps that are checked for missing keys far more often than they're written.
+func WithKeyFilter[K comparable, V any]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.keyFilter = true
+	}
+}
+
+// A keyFilter is a Bloom filter that can be read without locking. Bits are only ever set, so deleted keys linger as
+// false positives until the filter fills up and is rebuilt from the live keys.
+type keyFilter[K comparable] struct {
+	seed maphash.Seed
+	bits []atomic.Uint64
+	// added and capacity are only used under the OrdMap's write lock.
+	added    int
+	capacity int
+}
+
+func newKeyFilter[K comparable](capacity int) *keyFilter[K] {
+	capacity = max(capacity, minFilterCapacity)
+	return &keyFilter[K]{
+		seed:     maphash.MakeSeed(),
+		bits:     make([]atomic.Uint64, (capacity*filterBitsPerKey+63)/64),
+		capacity: capacity,
+	}
+}
+
+// add sets the bits for key.
+func (f *keyFilter[K]) add(key K) {
+	h := maphash.Comparable(f.seed, key)
+	size := uint64(len(f.bits)) * 64
+	for i := range uint64(filterHashes) {
+		bit := (h + i*(h>>32|1)) % size
+		f.bits[bit/64].Or(1 << (bit % 64))
+	}
+
+	f.added++
+}
+
+// mayContain reports false if key has definitely never been added.
+func (f *keyFilter[K]) mayContain(key K) bool {
+	h := maphash.Comparable(f.seed, key)
+	size := uint64(len(f.bits)) * 64
+	for i := range uint64(filterHashes) {
+		bit := (h + i*(h>>32|1)) % size
+		if f.bits[bit/64].Load()&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterAdd records a newly inserted key in the key filter, first replacing the filter with one sized for twice the
+// live keys if it's full. The write lock must be held by the caller, and key must already be in the lookup map.
+func (om *OrdMap[K, V]) filterAdd(key K) {
+	if !om.opts.keyFilter {
+		return
+	}
+
+	f := om.filter.Load()
+	if f == nil || f.added >= f.capacity {
+		f = newKeyFilter[K](2 * len(om.lookup))
+		for live := range om.lookup {
+			f.add(live)
+		}
+
+		om.filter.Store(f)
+		return
+	}
+
+	f.add(key)
+}
@@ -0,0 +1,55 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_KeyFilter(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithKeyFilter[string, int](), ordmap.WithKeyNormalizer[string, int](strings.ToLower))
+	for i := range 1000 {
+		om.Set(fmt.Sprintf("key %d", i), i)
+		if i%3 == 0 {
+			om.Delete(fmt.Sprintf("key %d", i))
+		}
+	}
+
+	for i := range 2000 {
+		key := fmt.Sprintf("KEY %d", i)
+		if expected := i < 1000 && i%3 != 0; om.Has(key) != expected {
+			t.Fatalf("expected Has(%q) to be %t", key, expected)
+		}
+	}
+
+	if err := om.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_KeyFilterConcurrentHas(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithKeyFilter[int, int]())
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 1000 {
+			om.Set(i, i)
+		}
+	}()
+
+	for i := range 1000 {
+		om.Has(-i)
+		om.Has(i)
+	}
+
+	wg.Wait()
+	for i := range 1000 {
+		if !om.Has(i) || om.Has(-i-1) {
+			t.Fatalf("expected only %d to be present", i)
+		}
+	}
+}
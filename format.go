@@ -0,0 +1,45 @@
+package ordmap
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// String formats the OrdMap like fmt formats a built-in map, as ordmap[k1:v1 k2:v2], but with entries in the
+// OrdMap's order, so the output is deterministic.
+func (om *OrdMap[K, V]) String() string {
+	var b strings.Builder
+	b.WriteString("ordmap[")
+	first := true
+	for key, val := range om.All() {
+		if !first {
+			b.WriteByte(' ')
+		}
+
+		first = false
+		fmt.Fprintf(&b, "%v:%v", key, val)
+	}
+
+	b.WriteByte(']')
+	return b.String()
+}
+
+// GoString formats the OrdMap like the %#v verb formats a built-in map, as
+// ordmap.OrdMap[string,int]{"k1":1, "k2":2}, with entries in the OrdMap's order.
+func (om *OrdMap[K, V]) GoString() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ordmap.OrdMap[%s,%s]{", reflect.TypeFor[K](), reflect.TypeFor[V]())
+	first := true
+	for key, val := range om.All() {
+		if !first {
+			b.WriteString(", ")
+		}
+
+		first = false
+		fmt.Fprintf(&b, "%#v:%#v", key, val)
+	}
+
+	b.WriteByte('}')
+	return b.String()
+}
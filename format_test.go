@@ -0,0 +1,28 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Format(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	if got := om.String(); got != "ordmap[]" {
+		t.Fatalf("expected ordmap[], got %s", got)
+	}
+
+	om.Set("b", 2)
+	om.Set("a", 1)
+	om.Set("c", 3)
+	om.Delete("c")
+
+	if got := fmt.Sprint(&om); got != "ordmap[b:2 a:1]" {
+		t.Fatalf("expected ordmap[b:2 a:1], got %s", got)
+	}
+
+	if got := fmt.Sprintf("%#v", &om); got != `ordmap.OrdMap[string,int]{"b":2, "a":1}` {
+		t.Fatalf(`expected ordmap.OrdMap[string,int]{"b":2, "a":1}, got %s`, got)
+	}
+}
@@ -0,0 +1,83 @@
+package ordmap
+
+import (
+	"context"
+	"iter"
+)
+
+// A Frozen is an immutable snapshot of an OrdMap. It has no mutating methods, so it can be shared between any number
+// of goroutines with a type-level guarantee that nobody changes it, and since nothing can change it its reads never
+// take a lock.
+type Frozen[K comparable, V any] struct {
+	lookup map[K]int
+	data   []Entry[K, V]
+}
+
+// Freeze returns an immutable snapshot of the OrdMap's current live entries. Later changes to the OrdMap are not
+// reflected in the snapshot. Freezing copies the entries and lookup map, so it's O(Len).
+func (om *OrdMap[K, V]) Freeze() Frozen[K, V] {
+	return frozenOf(om.snapshot())
+}
+
+// frozenOf wraps data, which must not be referenced anywhere else, in a Frozen.
+func frozenOf[K comparable, V any](data []Entry[K, V]) Frozen[K, V] {
+	lookup := make(map[K]int, len(data))
+	for idx, entry := range data {
+		lookup[entry.Key] = idx
+	}
+
+	return Frozen[K, V]{lookup: lookup, data: data}
+}
+
+// Entries returns a newly allocated, ordered slice of Entry structs which can be iterated on. A copy is returned so
+// that the snapshot itself can never be modified.
+func (f Frozen[K, V]) Entries() []Entry[K, V] {
+	entries := make([]Entry[K, V], len(f.data))
+	copy(entries, f.data)
+	return entries
+}
+
+// All returns an iterator over the snapshot's key/value pairs in order.
+func (f Frozen[K, V]) All() iter.Seq2[K, V] {
+	return f.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the snapshot's key/value pairs in order. Iteration stops as soon as ctx is
+// cancelled.
+func (f Frozen[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, entry := range f.data {
+			if ctx.Err() != nil || !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Get implements a lock-free map lookup.
+func (f Frozen[K, V]) Get(key K) (V, bool) {
+	idx, ok := f.lookup[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return f.data[idx].Value, true
+}
+
+// Index returns the ordered index associated with the given key.
+func (f Frozen[K, V]) Index(key K) (int, bool) {
+	idx, ok := f.lookup[key]
+	return idx, ok
+}
+
+// Has works the same as Get but does not return the value. It's included for convenience.
+func (f Frozen[K, V]) Has(key K) bool {
+	_, ok := f.lookup[key]
+	return ok
+}
+
+// Len returns the length of the snapshot.
+func (f Frozen[K, V]) Len() int {
+	return len(f.data)
+}
@@ -0,0 +1,36 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Freeze(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+	om.Delete("b")
+
+	var frozen ordmap.ReadOnly[string, int] = om.Freeze()
+	om.Set("a", 10)
+	om.Set("d", 4)
+
+	if frozen.Len() != 2 || frozen.Has("b") || frozen.Has("d") {
+		t.Fatalf("expected snapshot to hold a and c only, got %v", frozen.Entries())
+	}
+
+	if val, _ := frozen.Get("a"); val != 1 {
+		t.Fatalf("expected snapshot to keep a=1, got %d", val)
+	}
+
+	if idx, _ := frozen.Index("c"); idx != 1 {
+		t.Fatalf("expected c to be at index 1, got %d", idx)
+	}
+
+	frozen.Entries()[0].Value = 100
+	if val, _ := frozen.Get("a"); val != 1 {
+		t.Fatal("expected modifying returned entries to not affect the snapshot")
+	}
+}
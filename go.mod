@@ -1,3 +1,3 @@
 module github.com/eriktate/go-ordmap
 
-go 1.22.4
+go 1.24
@@ -0,0 +1,19 @@
+package ordmap
+
+// WithOnSet registers a callback invoked with every key/value pair set on the OrdMap, whether the key is new or not.
+// Callbacks run in the order the sets happened, after the write lock has been released, so they may safely use the
+// OrdMap.
+func WithOnSet[K comparable, V any](fn func(K, V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.onSet = fn
+	}
+}
+
+// WithOnDelete registers a callback invoked with the key and last value of every entry removed from the OrdMap,
+// including entries that were evicted or expired. Callbacks run after the write lock has been released, in the same
+// way as WithOnSet.
+func WithOnDelete[K comparable, V any](fn func(K, V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.onDelete = fn
+	}
+}
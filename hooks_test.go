@@ -0,0 +1,38 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Hooks(t *testing.T) {
+	var events []string
+	var om ordmap.OrdMap[string, int]
+	om = ordmap.NewLRU(2,
+		ordmap.WithOnSet(func(key string, val int) {
+			// hooks run outside the lock, so reading the map here mustn't deadlock
+			events = append(events, fmt.Sprintf("set %s=%d len=%d", key, val, om.Len()))
+		}),
+		ordmap.WithOnDelete(func(key string, val int) {
+			events = append(events, fmt.Sprintf("delete %s=%d", key, val))
+		}),
+		ordmap.WithOnEvict(func(key string, val int) {
+			events = append(events, fmt.Sprintf("evict %s=%d", key, val))
+		}),
+	)
+
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("a", 3)
+	om.Set("c", 4)
+	om.Delete("a")
+	om.SwapDelete("c")
+	om.Delete("missing")
+
+	expected := "[set a=1 len=1 set b=2 len=2 set a=3 len=2 set c=4 len=2 delete b=2 evict b=2 delete a=3 delete c=4]"
+	if got := fmt.Sprint(events); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
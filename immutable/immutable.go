@@ -0,0 +1,137 @@
+// Package immutable provides a persistent ordered map. Every modification returns a new map that shares most of its
+// structure with the old one, so old versions remain valid and unchanged, and any number of goroutines can read any
+// version without locks.
+package immutable
+
+import (
+	"context"
+	"hash/maphash"
+	"iter"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+// A slot records where a key lives in the ordering.
+type slot[K comparable] struct {
+	key K
+	seq uint64
+}
+
+// A Map is an immutable, insertion ordered map. The zero value is not usable; create maps with New. Get, Set, and
+// Delete are O(log n), and Set and Delete return a new Map rather than modifying the receiver.
+type Map[K comparable, V any] struct {
+	seed maphash.Seed
+	// byHash maps key hashes to the keys sharing that hash and their sequence numbers, and bySeq holds the entries
+	// themselves ordered by insertion sequence.
+	byHash *tree[[]slot[K]]
+	bySeq  *tree[ordmap.Entry[K, V]]
+	next   uint64
+	size   int
+}
+
+// New returns a new, empty Map.
+func New[K comparable, V any]() Map[K, V] {
+	return Map[K, V]{seed: maphash.MakeSeed()}
+}
+
+// find returns the sequence number of key along with its hash bucket.
+func (m Map[K, V]) find(key K) (uint64, []slot[K], uint64, bool) {
+	hash := maphash.Comparable(m.seed, key)
+	bucket, _ := m.byHash.get(hash)
+	for _, s := range bucket {
+		if s.key == key {
+			return hash, bucket, s.seq, true
+		}
+	}
+
+	return hash, bucket, 0, false
+}
+
+// Get returns the value for key.
+func (m Map[K, V]) Get(key K) (V, bool) {
+	_, _, seq, ok := m.find(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	entry, _ := m.bySeq.get(seq)
+	return entry.Value, true
+}
+
+// Has reports whether key is present.
+func (m Map[K, V]) Has(key K) bool {
+	_, _, _, ok := m.find(key)
+	return ok
+}
+
+// Len returns the number of entries.
+func (m Map[K, V]) Len() int {
+	return m.size
+}
+
+// Set returns a new Map with key set to val. Existing keys keep their position, while new keys are appended to the
+// end of the ordering. The receiver is left unchanged.
+func (m Map[K, V]) Set(key K, val V) Map[K, V] {
+	hash, bucket, seq, ok := m.find(key)
+	if !ok {
+		seq = m.next
+		m.next++
+		m.size++
+		m.byHash = m.byHash.insert(hash, append(bucket[:len(bucket):len(bucket)], slot[K]{key: key, seq: seq}))
+	}
+
+	m.bySeq = m.bySeq.insert(seq, ordmap.Entry[K, V]{Key: key, Value: val})
+	return m
+}
+
+// Delete returns a new Map without key. The receiver is left unchanged.
+func (m Map[K, V]) Delete(key K) Map[K, V] {
+	hash, bucket, seq, ok := m.find(key)
+	if !ok {
+		return m
+	}
+
+	if len(bucket) == 1 {
+		m.byHash = m.byHash.remove(hash)
+	} else {
+		rest := make([]slot[K], 0, len(bucket)-1)
+		for _, s := range bucket {
+			if s.key != key {
+				rest = append(rest, s)
+			}
+		}
+		m.byHash = m.byHash.insert(hash, rest)
+	}
+
+	m.bySeq = m.bySeq.remove(seq)
+	m.size--
+	return m
+}
+
+// Entries returns a newly allocated, ordered slice of the Map's entries.
+func (m Map[K, V]) Entries() []ordmap.Entry[K, V] {
+	entries := make([]ordmap.Entry[K, V], 0, m.size)
+	for entry := range m.bySeq.all() {
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// All returns an iterator over the Map's key/value pairs in order.
+func (m Map[K, V]) All() iter.Seq2[K, V] {
+	return m.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the Map's key/value pairs in order. Iteration stops as soon as ctx is cancelled.
+// Since the Map can't change, the loop body is free to derive new maps from it.
+func (m Map[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for entry := range m.bySeq.all() {
+			if ctx.Err() != nil || !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
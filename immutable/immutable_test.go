@@ -0,0 +1,61 @@
+package immutable_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap/immutable"
+)
+
+func Test_Persistence(t *testing.T) {
+	empty := immutable.New[string, int]()
+	v1 := empty.Set("a", 1).Set("b", 2).Set("c", 3)
+	v2 := v1.Set("a", 10).Delete("b").Set("d", 4)
+
+	if empty.Len() != 0 || v1.Len() != 3 || v2.Len() != 3 {
+		t.Fatalf("unexpected lengths %d %d %d", empty.Len(), v1.Len(), v2.Len())
+	}
+
+	if fmt.Sprint(v1.Entries()) != "[{a 1} {b 2} {c 3}]" {
+		t.Fatalf("expected v1 to be unaffected by later changes, got %v", v1.Entries())
+	}
+
+	if fmt.Sprint(v2.Entries()) != "[{a 10} {c 3} {d 4}]" {
+		t.Fatalf("unexpected v2 entries %v", v2.Entries())
+	}
+
+	if _, ok := v2.Get("b"); ok || !v1.Has("b") {
+		t.Fatal("expected b to only be deleted from v2")
+	}
+}
+
+func Test_ManyVersions(t *testing.T) {
+	m := immutable.New[int, int]()
+	versions := make([]immutable.Map[int, int], 0, 1000)
+	for i := 0; i < 1000; i++ {
+		m = m.Set(i, i)
+		versions = append(versions, m)
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		m = m.Delete(i)
+	}
+
+	for i, version := range versions {
+		if version.Len() != i+1 {
+			t.Fatalf("expected version %d to hold %d entries, got %d", i, i+1, version.Len())
+		}
+	}
+
+	idx := 0
+	for key, val := range m.All() {
+		if key != idx*2+1 || val != key {
+			t.Fatalf("expected entry #%d to be %d, got %d=%d", idx, idx*2+1, key, val)
+		}
+		idx++
+	}
+
+	if idx != 500 {
+		t.Fatalf("expected 500 entries to remain, got %d", idx)
+	}
+}
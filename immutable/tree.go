@@ -0,0 +1,142 @@
+package immutable
+
+import "iter"
+
+// A tree is a node of a persistent AVL tree keyed by uint64. Trees are never modified once built: insert and remove
+// copy the O(log n) nodes along the path to the change and share every other node with the original tree. A nil
+// *tree is the empty tree.
+type tree[T any] struct {
+	key    uint64
+	val    T
+	left   *tree[T]
+	right  *tree[T]
+	height int
+}
+
+// depth returns the height of t, treating the empty tree as 0.
+func (t *tree[T]) depth() int {
+	if t == nil {
+		return 0
+	}
+
+	return t.height
+}
+
+// with returns a copy of t with new children and a recomputed height.
+func (t *tree[T]) with(left, right *tree[T]) *tree[T] {
+	return &tree[T]{
+		key:    t.key,
+		val:    t.val,
+		left:   left,
+		right:  right,
+		height: max(left.depth(), right.depth()) + 1,
+	}
+}
+
+// get returns the value stored under key.
+func (t *tree[T]) get(key uint64) (T, bool) {
+	for t != nil {
+		switch {
+		case key < t.key:
+			t = t.left
+		case key > t.key:
+			t = t.right
+		default:
+			return t.val, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// insert returns a tree with val stored under key, replacing any existing value.
+func (t *tree[T]) insert(key uint64, val T) *tree[T] {
+	if t == nil {
+		return &tree[T]{key: key, val: val, height: 1}
+	}
+
+	switch {
+	case key < t.key:
+		return t.with(t.left.insert(key, val), t.right).balance()
+	case key > t.key:
+		return t.with(t.left, t.right.insert(key, val)).balance()
+	default:
+		return &tree[T]{key: key, val: val, left: t.left, right: t.right, height: t.height}
+	}
+}
+
+// remove returns a tree without key.
+func (t *tree[T]) remove(key uint64) *tree[T] {
+	if t == nil {
+		return nil
+	}
+
+	switch {
+	case key < t.key:
+		return t.with(t.left.remove(key), t.right).balance()
+	case key > t.key:
+		return t.with(t.left, t.right.remove(key)).balance()
+	}
+
+	if t.left == nil {
+		return t.right
+	}
+
+	if t.right == nil {
+		return t.left
+	}
+
+	// replace the removed node with its in-order successor
+	succ := t.right
+	for succ.left != nil {
+		succ = succ.left
+	}
+
+	return (&tree[T]{key: succ.key, val: succ.val}).with(t.left, t.right.remove(succ.key)).balance()
+}
+
+// balance restores the AVL invariant at t after one of its subtrees changed height by at most one.
+func (t *tree[T]) balance() *tree[T] {
+	diff := t.left.depth() - t.right.depth()
+	switch {
+	case diff > 1:
+		left := t.left
+		if left.left.depth() < left.right.depth() {
+			left = left.rotateLeft()
+		}
+		return t.with(left, t.right).rotateRight()
+	case diff < -1:
+		right := t.right
+		if right.right.depth() < right.left.depth() {
+			right = right.rotateRight()
+		}
+		return t.with(t.left, right).rotateLeft()
+	default:
+		return t
+	}
+}
+
+func (t *tree[T]) rotateLeft() *tree[T] {
+	return t.right.with(t.with(t.left, t.right.left), t.right.right)
+}
+
+func (t *tree[T]) rotateRight() *tree[T] {
+	return t.left.with(t.left.left, t.with(t.left.right, t.right))
+}
+
+// all returns an iterator over the tree's values in key order.
+func (t *tree[T]) all() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.walk(yield)
+	}
+}
+
+// walk visits the tree in order, returning false if yield asked to stop.
+func (t *tree[T]) walk(yield func(T) bool) bool {
+	if t == nil {
+		return true
+	}
+
+	return t.left.walk(yield) && yield(t.val) && t.right.walk(yield)
+}
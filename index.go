@@ -0,0 +1,101 @@
+package ordmap
+
+import (
+	"slices"
+	"time"
+)
+
+// A valueIndex is an auxiliary structure that an OrdMap keeps in sync with its entries' values.
+type valueIndex[K comparable, V any] interface {
+	insert(key K, val V)
+	remove(key K, val V)
+}
+
+// A secondaryIndex groups an OrdMap's keys by an index key derived from their values.
+type secondaryIndex[K comparable, V any, I comparable] struct {
+	fn   func(V) I
+	keys map[I]map[K]struct{}
+}
+
+func (si *secondaryIndex[K, V, I]) insert(key K, val V) {
+	ik := si.fn(val)
+	keys, ok := si.keys[ik]
+	if !ok {
+		keys = make(map[K]struct{})
+		si.keys[ik] = keys
+	}
+
+	keys[key] = struct{}{}
+}
+
+func (si *secondaryIndex[K, V, I]) remove(key K, val V) {
+	ik := si.fn(val)
+	delete(si.keys[ik], key)
+	if len(si.keys[ik]) == 0 {
+		delete(si.keys, ik)
+	}
+}
+
+// AddIndex adds a secondary index called name to om, grouping its entries by the index key fn returns for their
+// values. The index is built from the current entries and kept up to date on every following write, so fn has to be
+// deterministic: it's called again with the old value to find what to remove. Adding an index with a name that's
+// already in use replaces it.
+//
+// Every write pays an extra fn call and map update per index, in exchange for ByIndex not having to scan the map.
+func AddIndex[K comparable, V any, I comparable](om *OrdMap[K, V], name string, fn func(V) I) {
+	om.m.Lock()
+	defer om.m.Unlock()
+	index := &secondaryIndex[K, V, I]{fn: fn, keys: make(map[I]map[K]struct{})}
+	for idx, entry := range om.data {
+		if om.live(idx) {
+			index.insert(entry.Key, entry.Value)
+		}
+	}
+
+	if om.indexes == nil {
+		om.indexes = make(map[string]valueIndex[K, V])
+	}
+
+	om.indexes[name] = index
+}
+
+// ByIndex returns the entries of om whose values map to indexKey in the index called name, in om's order. It returns
+// nil if there is no index called name, or if its index keys aren't of type I.
+func ByIndex[K comparable, V any, I comparable](om *OrdMap[K, V], name string, indexKey I) []Entry[K, V] {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	index, ok := om.indexes[name].(*secondaryIndex[K, V, I])
+	if !ok {
+		return nil
+	}
+
+	matches := make([]int, 0, len(index.keys[indexKey]))
+	for key := range index.keys[indexKey] {
+		matches = append(matches, om.lookup[key])
+	}
+
+	slices.Sort(matches)
+	now := time.Now()
+	entries := make([]Entry[K, V], 0, len(matches))
+	for _, idx := range matches {
+		if om.visible(idx, now) {
+			entries = append(entries, om.data[idx])
+		}
+	}
+
+	return entries
+}
+
+// addToIndexes adds an entry to every secondary index. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) addToIndexes(key K, val V) {
+	for _, index := range om.indexes {
+		index.insert(key, val)
+	}
+}
+
+// removeFromIndexes removes an entry from every secondary index. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) removeFromIndexes(key K, val V) {
+	for _, index := range om.indexes {
+		index.remove(key, val)
+	}
+}
@@ -0,0 +1,54 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+type session struct {
+	user string
+	id   int
+}
+
+func Test_Index(t *testing.T) {
+	om := ordmap.New[string, session](0)
+	om.Set("s1", session{user: "alice", id: 1})
+	om.Set("s2", session{user: "bob", id: 2})
+	ordmap.AddIndex(&om, "user", func(s session) string { return s.user })
+
+	om.Set("s3", session{user: "alice", id: 3})
+	om.Set("s4", session{user: "bob", id: 4})
+	om.Set("s2", session{user: "alice", id: 2})
+	om.Delete("s1")
+
+	userKeys := func(user string) string {
+		var keys []string
+		for _, entry := range ordmap.ByIndex(&om, "user", user) {
+			keys = append(keys, entry.Key)
+		}
+
+		return fmt.Sprint(keys)
+	}
+
+	if got := userKeys("alice"); got != "[s2 s3]" {
+		t.Fatalf("expected alice to have sessions [s2 s3], got %s", got)
+	}
+
+	if got := userKeys("bob"); got != "[s4]" {
+		t.Fatalf("expected bob to have sessions [s4], got %s", got)
+	}
+
+	if got := ordmap.ByIndex(&om, "user", "carol"); len(got) != 0 {
+		t.Fatalf("expected no sessions for carol, got %v", got)
+	}
+
+	if got := ordmap.ByIndex(&om, "user", 1); got != nil {
+		t.Fatalf("expected nil for an index key of the wrong type, got %v", got)
+	}
+
+	if got := ordmap.ByIndex(&om, "missing", "alice"); got != nil {
+		t.Fatalf("expected nil for a missing index, got %v", got)
+	}
+}
@@ -0,0 +1,17 @@
+package ordmap
+
+import "unique"
+
+// WithInternedKeys makes an OrdMap intern its string keys as they are inserted, so identical keys that are repeatedly
+// re-ingested from parsed input share a single canonical allocation instead of each pinning their own copy (or the
+// whole input buffer they were sliced from). Interned strings are released once nothing references them anymore.
+func WithInternedKeys[V any]() Option[string, V] {
+	return func(o *options[string, V]) {
+		o.intern = internString
+	}
+}
+
+// internString returns the canonical copy of s.
+func internString(s string) string {
+	return unique.Make(s).Value()
+}
@@ -0,0 +1,34 @@
+package ordmap_test
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_InternedKeys(t *testing.T) {
+	a := ordmap.New(0, ordmap.WithInternedKeys[int]())
+	b := ordmap.New(0, ordmap.WithInternedKeys[int]())
+
+	input := strings.Repeat("x", 64) + ",key"
+	key := input[len(input)-3:]
+	a.Set(key, 1)
+	b.Set(strings.Clone(key), 2)
+
+	keyA := a.Entries()[0].Key
+	keyB := b.Entries()[0].Key
+	if keyA != "key" || unsafe.StringData(keyA) != unsafe.StringData(keyB) {
+		t.Fatal("expected identical keys in separate maps to share an interned allocation")
+	}
+
+	if unsafe.StringData(keyA) == unsafe.StringData(key) {
+		t.Fatal("expected interned key to not reference the original input buffer")
+	}
+
+	a.Set(strings.Clone(key), 3)
+	if unsafe.StringData(a.Entries()[0].Key) != unsafe.StringData(keyA) {
+		t.Fatal("expected updating a key to keep the stored interned key")
+	}
+}
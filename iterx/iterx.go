@@ -0,0 +1,91 @@
+// Package iterx provides lazy, chainable adapters over iter.Seq2 key/value sequences such as those produced by
+// OrdMap.All. Adapters never buffer, so a pipeline only does as much work as its consumer asks for.
+package iterx
+
+import (
+	"iter"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+// Filter yields only the pairs from seq for which keep returns true.
+func Filter[K, V any](seq iter.Seq2[K, V], keep func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key, val := range seq {
+			if keep(key, val) && !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// MapVal yields every pair from seq with its value replaced by the result of fn.
+func MapVal[K, V, R any](seq iter.Seq2[K, V], fn func(K, V) R) iter.Seq2[K, R] {
+	return func(yield func(K, R) bool) {
+		for key, val := range seq {
+			if !yield(key, fn(key, val)) {
+				return
+			}
+		}
+	}
+}
+
+// Take yields at most the first n pairs from seq.
+func Take[K, V any](seq iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if n <= 0 {
+			return
+		}
+
+		count := 0
+		for key, val := range seq {
+			if !yield(key, val) {
+				return
+			}
+
+			count++
+			if count >= n {
+				return
+			}
+		}
+	}
+}
+
+// Drop skips the first n pairs from seq and yields the rest.
+func Drop[K, V any](seq iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		count := 0
+		for key, val := range seq {
+			if count < n {
+				count++
+				continue
+			}
+
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// While yields pairs from seq until cond returns false for the first time.
+func While[K, V any](seq iter.Seq2[K, V], cond func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key, val := range seq {
+			if !cond(key, val) || !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// Collect drains seq into a new OrdMap, preserving the order pairs were yielded in. Later pairs overwrite the values
+// of earlier pairs with the same key without changing their position.
+func Collect[K comparable, V any](seq iter.Seq2[K, V]) *ordmap.OrdMap[K, V] {
+	om := ordmap.New[K, V](0)
+	for key, val := range seq {
+		om.Set(key, val)
+	}
+
+	return &om
+}
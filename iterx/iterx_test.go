@@ -0,0 +1,32 @@
+package iterx_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+	"github.com/eriktate/go-ordmap/iterx"
+)
+
+func Test_Pipeline(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := 0; i < 100; i++ {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	even := iterx.Filter(om.All(), func(_ string, val int) bool { return val%2 == 0 })
+	doubled := iterx.MapVal(even, func(_ string, val int) int { return val * 2 })
+	small := iterx.While(iterx.Take(iterx.Drop(doubled, 5), 20), func(_ string, val int) bool { return val < 50 })
+	res := iterx.Collect(small)
+
+	expected := []int{20, 24, 28, 32, 36, 40, 44, 48}
+	if res.Len() != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), res.Len())
+	}
+
+	for idx, entry := range res.Entries() {
+		if entry.Key != fmt.Sprintf("key %d", expected[idx]/2) || entry.Value != expected[idx] {
+			t.Fatalf("expected entry #%d to be %d, received key=%s val=%d", idx, expected[idx], entry.Key, entry.Value)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package ordmap
+
+// A journalEntry records enough about a single Set or Delete to both reverse and replay it.
+type journalEntry[K comparable, V any] struct {
+	op   Op
+	key  K
+	val  V
+	prev V
+	// existed is set for Sets that updated an existing key rather than inserting a new one.
+	existed bool
+	// before is the key that followed a deleted entry, so an undo can put it back in the same position. It's only
+	// meaningful when hasBefore is set; otherwise the entry was last.
+	before    K
+	hasBefore bool
+}
+
+// A journal holds the undo and redo stacks of an OrdMap.
+type journal[K comparable, V any] struct {
+	size int
+	undo []journalEntry[K, V]
+	redo []journalEntry[K, V]
+	// replaying is set while Undo and Redo apply entries, so those changes aren't journaled themselves.
+	replaying bool
+}
+
+func newJournal[K comparable, V any](size int) *journal[K, V] {
+	if size <= 0 {
+		return nil
+	}
+
+	return &journal[K, V]{size: size}
+}
+
+// WithJournal records the most recent size Sets and Deletes so they can be rolled back with Undo and forward again
+// with Redo, making it possible to use an OrdMap as an editor's document model. Undoing a Delete restores the entry at
+// its original position. Reordering caused by SwapDelete, access ordering, or WithMoveOnUpdate is not restored.
+func WithJournal[K comparable, V any](size int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.journalSize = size
+	}
+}
+
+// Undo reverts up to the n most recent journaled operations, newest first, and returns how many were reverted.
+// Reverted operations can be reapplied with Redo until a new operation is made. Undo does nothing on maps created
+// without WithJournal.
+func (om *OrdMap[K, V]) Undo(n int) int {
+	om.m.Lock()
+	defer om.unlock()
+	if om.journal == nil {
+		return 0
+	}
+
+	j := om.journal
+	j.replaying = true
+	defer func() { j.replaying = false }()
+
+	count := 0
+	for ; count < n && len(j.undo) > 0; count++ {
+		entry := j.undo[len(j.undo)-1]
+		j.undo = j.undo[:len(j.undo)-1]
+		switch {
+		case entry.op == OpSet && entry.existed:
+			om.set(Entry[K, V]{Key: entry.key, Value: entry.prev})
+		case entry.op == OpSet:
+			om.delete(entry.key)
+		case entry.op == OpDelete:
+			om.restore(entry)
+		}
+
+		j.redo = append(j.redo, entry)
+	}
+
+	return count
+}
+
+// Redo reapplies up to n operations reverted by Undo, oldest first, and returns how many were reapplied.
+func (om *OrdMap[K, V]) Redo(n int) int {
+	om.m.Lock()
+	defer om.unlock()
+	if om.journal == nil {
+		return 0
+	}
+
+	j := om.journal
+	j.replaying = true
+	defer func() { j.replaying = false }()
+
+	count := 0
+	for ; count < n && len(j.redo) > 0; count++ {
+		entry := j.redo[len(j.redo)-1]
+		j.redo = j.redo[:len(j.redo)-1]
+		switch entry.op {
+		case OpSet:
+			om.set(Entry[K, V]{Key: entry.key, Value: entry.val})
+		case OpDelete:
+			om.delete(entry.key)
+		}
+
+		j.push(entry)
+	}
+
+	return count
+}
+
+// push adds an entry to the undo stack, dropping the oldest entry once the stack is full.
+func (j *journal[K, V]) push(entry journalEntry[K, V]) {
+	if len(j.undo) == j.size {
+		clear(j.undo[:1])
+		j.undo = j.undo[1:]
+	}
+
+	j.undo = append(j.undo, entry)
+}
+
+// journalSet records a Set of entry. existed and prev describe the key's value before the Set. The write lock must be
+// held by the caller.
+func (om *OrdMap[K, V]) journalSet(entry Entry[K, V], prev V, existed bool) {
+	j := om.journal
+	if j.replaying {
+		return
+	}
+
+	j.push(journalEntry[K, V]{op: OpSet, key: entry.Key, val: entry.Value, prev: prev, existed: existed})
+	j.redo = j.redo[:0]
+}
+
+// journalDelete records the Delete of the entry at idx along with the key that follows it. The write lock must be
+// held by the caller.
+func (om *OrdMap[K, V]) journalDelete(idx int) {
+	j := om.journal
+	if j.replaying {
+		return
+	}
+
+	je := journalEntry[K, V]{op: OpDelete, key: om.data[idx].Key, prev: om.data[idx].Value}
+	for next := idx + 1; next < len(om.data); next++ {
+		if om.live(next) {
+			je.before, je.hasBefore = om.data[next].Key, true
+			break
+		}
+	}
+
+	j.push(je)
+	j.redo = j.redo[:0]
+}
+
+// restore reinserts a deleted entry in front of the key that followed it when it was deleted. Journal entries are
+// undone strictly in reverse, but reinserting the entry can evict that key from a bounded map, in which case the entry
+// is left at the end instead. This is O(n). The write lock must be held by the caller.
+func (om *OrdMap[K, V]) restore(entry journalEntry[K, V]) {
+	om.set(Entry[K, V]{Key: entry.key, Value: entry.prev})
+	if !entry.hasBefore {
+		return
+	}
+
+	if _, ok := om.lookup[entry.key]; !ok {
+		return
+	}
+
+	om.sweep()
+	if to, ok := om.lookup[entry.before]; ok {
+		om.moveTo(entry.key, to)
+	}
+}
@@ -0,0 +1,72 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Journal(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithJournal[string, int](10))
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+	base := fmt.Sprint(om.Entries())
+
+	om.Set("b", 20)
+	om.Delete("b")
+	om.Delete("a")
+	om.Set("d", 4)
+	edited := fmt.Sprint(om.Entries())
+
+	if undone := om.Undo(4); undone != 4 {
+		t.Fatalf("expected to undo 4 operations, undid %d", undone)
+	}
+
+	if got := fmt.Sprint(om.Entries()); got != base {
+		t.Fatalf("expected undo to restore %s, got %s", base, got)
+	}
+
+	if redone := om.Redo(10); redone != 4 {
+		t.Fatalf("expected to redo 4 operations, redid %d", redone)
+	}
+
+	if got := fmt.Sprint(om.Entries()); got != edited {
+		t.Fatalf("expected redo to restore %s, got %s", edited, got)
+	}
+
+	om.Undo(1)
+	om.Set("e", 5)
+	if om.Redo(1) != 0 {
+		t.Fatal("expected a new operation to clear the redo stack")
+	}
+
+	if got := om.Undo(100); got != 7 || om.Len() != 0 {
+		t.Fatalf("expected to undo all 7 journaled operations back to an empty map, undid %d", got)
+	}
+
+	bounded := ordmap.New(0, ordmap.WithJournal[string, int](2))
+	bounded.Set("a", 1)
+	bounded.Set("b", 2)
+	bounded.Set("c", 3)
+	if got := bounded.Undo(10); got != 2 || !bounded.Has("a") {
+		t.Fatalf("expected the journal to only keep the last 2 operations, undid %d", got)
+	}
+}
+
+func Test_JournalRestoreMissingNeighbor(t *testing.T) {
+	om := ordmap.NewLRU(2, ordmap.WithJournal[string, int](10))
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+
+	// undoing the eviction of a reinserts it in front of b, but that overflows the map again and evicts b first
+	if om.Undo(1) != 1 {
+		t.Fatal("expected to undo the eviction")
+	}
+
+	if got := fmt.Sprint(keys[string, int](&om)); got != "[c a]" {
+		t.Fatalf("expected a to be restored at the end, got %s", got)
+	}
+}
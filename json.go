@@ -0,0 +1,170 @@
+package ordmap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// IsZero reports whether the OrdMap is nil or empty, so that fields holding one are left out by encoders honoring
+// omitzero, such as encoding/json. This works for fields of both type OrdMap and *OrdMap.
+func (om *OrdMap[K, V]) IsZero() bool {
+	return om == nil || om.Len() == 0
+}
+
+// MarshalJSON encodes the OrdMap as a JSON object with its keys in the OrdMap's order. Keys are converted following
+// the same rules encoding/json uses for built-in maps: they must be strings, integers, or implement
+// encoding.TextMarshaler. An empty OrdMap encodes as {}.
+//
+// Since an OrdMap holds a lock it can't be copied, so MarshalJSON has a pointer receiver, and encoding/json only calls
+// it on addressable values. A field of type OrdMap is encoded properly when the enclosing struct is marshalled through
+// a pointer, but marshalling the struct by value silently encodes the field as {}; go vet reports such calls as copying
+// a lock. Use a *OrdMap field, or always marshal the enclosing struct through a pointer. Unmarshalling works for both
+// kinds of field.
+func (om *OrdMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for key, val := range om.All() {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		name, err := marshalKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedKey, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedVal, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedVal)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON sets every member of a JSON object on the OrdMap, in the order they appear. Like decoding into a
+// built-in map, existing entries are kept, and null leaves the OrdMap unchanged. Keys are converted with the reverse
+// of MarshalJSON's rules.
+func (om *OrdMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok == nil {
+		return nil
+	}
+
+	if tok != json.Delim('{') {
+		return fmt.Errorf("ordmap: cannot unmarshal %v into an OrdMap", tok)
+	}
+
+	var entries []Entry[K, V]
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, err := unmarshalKey[K](tok.(string))
+		if err != nil {
+			return err
+		}
+
+		var val V
+		if err := dec.Decode(&val); err != nil {
+			return err
+		}
+
+		entries = append(entries, Entry[K, V]{Key: key, Value: val})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	om.m.Lock()
+	defer om.unlock()
+	if om.lookup == nil {
+		om.lookup = make(map[K]int, len(entries))
+	}
+
+	for _, entry := range entries {
+		om.set(entry)
+	}
+
+	return nil
+}
+
+// marshalKey converts a key to the name of a JSON object member.
+func marshalKey[K comparable](key K) (string, error) {
+	rv := reflect.ValueOf(&key).Elem()
+	if rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+
+	if tm, ok := any(key).(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("ordmap: unsupported JSON key type %s", rv.Type())
+}
+
+// unmarshalKey converts the name of a JSON object member to a key.
+func unmarshalKey[K comparable](name string) (K, error) {
+	var key K
+	rv := reflect.ValueOf(&key).Elem()
+	if rv.Kind() == reflect.String {
+		rv.SetString(name)
+		return key, nil
+	}
+
+	if tu, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := tu.UnmarshalText([]byte(name))
+		return key, err
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, rv.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("ordmap: invalid JSON key %q: %w", name, err)
+		}
+
+		rv.SetInt(n)
+		return key, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(name, 10, rv.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("ordmap: invalid JSON key %q: %w", name, err)
+		}
+
+		rv.SetUint(n)
+		return key, nil
+	}
+
+	return key, fmt.Errorf("ordmap: unsupported JSON key type %s", rv.Type())
+}
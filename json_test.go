@@ -0,0 +1,93 @@
+package ordmap_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_JSON(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.Set("z", 1)
+	om.Set("a", 2)
+	om.Set("m", 3)
+
+	data, err := json.Marshal(&om)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling: %v", err)
+	}
+
+	if string(data) != `{"z":1,"a":2,"m":3}` {
+		t.Fatalf(`expected {"z":1,"a":2,"m":3}, got %s`, data)
+	}
+
+	var decoded ordmap.OrdMap[string, int]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error unmarshalling: %v", err)
+	}
+
+	if got := decoded.String(); got != "ordmap[z:1 a:2 m:3]" {
+		t.Fatalf("expected decoding to keep the order, got %s", got)
+	}
+
+	ints := ordmap.New[int, string](0)
+	ints.Set(10, "ten")
+	ints.Set(-1, "minus one")
+	data, _ = json.Marshal(&ints)
+	var decodedInts ordmap.OrdMap[int, string]
+	if err := json.Unmarshal(data, &decodedInts); err != nil || decodedInts.String() != "ordmap[10:ten -1:minus one]" {
+		t.Fatalf("expected int keys to round trip, got %s, %v", decodedInts.String(), err)
+	}
+}
+
+func Test_IsZero(t *testing.T) {
+	type wrapper struct {
+		Tags *ordmap.OrdMap[string, int] `json:"tags,omitzero"`
+	}
+
+	empty := ordmap.New[string, int](0)
+	for _, w := range []wrapper{{}, {Tags: &empty}} {
+		data, err := json.Marshal(w)
+		if err != nil || string(data) != "{}" {
+			t.Fatalf("expected a nil or empty map to be omitted, got %s, %v", data, err)
+		}
+	}
+
+	data, _ := json.Marshal(&empty)
+	if string(data) != "{}" {
+		t.Fatalf("expected an empty map to marshal as {}, got %s", data)
+	}
+
+	empty.Set("a", 1)
+	if empty.IsZero() {
+		t.Fatal("expected a non-empty map not to be zero")
+	}
+}
+
+func Test_JSONValueField(t *testing.T) {
+	type doc struct {
+		Tags ordmap.OrdMap[string, int] `json:"tags,omitzero"`
+	}
+
+	// map values aren't addressable either, which IsZero copes with
+	if data, err := json.Marshal(map[string]doc{"d": {}}); err != nil || string(data) != `{"d":{}}` {
+		t.Fatalf("expected an empty value field to be omitted, got %s, %v", data, err)
+	}
+
+	var d doc
+	if err := json.Unmarshal([]byte(`{"tags":{"z":1,"a":2}}`), &d); err != nil {
+		t.Fatalf("unexpected error unmarshalling: %v", err)
+	}
+
+	if data, err := json.Marshal(&d); err != nil || string(data) != `{"tags":{"z":1,"a":2}}` {
+		t.Fatalf("expected a value field to be encoded in order through a pointer, got %s, %v", data, err)
+	}
+
+	// a struct marshalled by value doesn't have addressable fields, so the documented limitation applies. vet flags
+	// passing it directly, so it's copied through reflection here.
+	if data, _ := json.Marshal(reflect.ValueOf(&d).Elem().Interface()); string(data) != `{"tags":{}}` {
+		t.Fatalf("expected a value field marshalled by value to fall back to {}, got %s", data)
+	}
+}
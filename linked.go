@@ -0,0 +1,334 @@
+package ordmap
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// A node is a single entry within a Linked map's list.
+type node[K comparable, V any] struct {
+	entry Entry[K, V]
+	prev  *node[K, V]
+	next  *node[K, V]
+}
+
+// A Linked is a concurrency safe ordered map backed by a doubly linked list plus a map of keys to list nodes. Compared
+// to OrdMap, deletes and relative reordering are always O(1) and never require compaction, at the cost of slower
+// iteration, O(n) Index lookups, and an allocation per entry unless WithSlabs is used.
+type Linked[K comparable, V any] struct {
+	m    sync.RWMutex
+	opts linkedOptions
+
+	lookup map[K]*node[K, V]
+	head   *node[K, V]
+	tail   *node[K, V]
+
+	// slab is the unused remainder of the most recently allocated slab and free is a list of nodes released by
+	// deletes, linked through their next pointers. Both are only used when slabs are enabled.
+	slab []node[K, V]
+	free *node[K, V]
+}
+
+// A LinkedOption configures optional behavior of a Linked map at construction time.
+type LinkedOption func(*linkedOptions)
+
+// linkedOptions holds the configuration assembled from the LinkedOptions passed to NewLinked.
+type linkedOptions struct {
+	slabSize int
+}
+
+// WithSlabs makes a Linked map allocate its nodes size at a time in contiguous slabs and recycle the nodes of deleted
+// entries through a freelist. This trades one allocation per entry for one per slab, which greatly reduces GC pressure
+// for maps that repeatedly grow and shrink by large amounts. Slabs are retained once allocated, so the map's memory
+// footprint stays at its peak and is reused by later inserts.
+func WithSlabs(size int) LinkedOption {
+	return func(o *linkedOptions) {
+		o.slabSize = size
+	}
+}
+
+// NewLinked returns a new, linked list backed ordered map.
+func NewLinked[K comparable, V any](opts ...LinkedOption) Linked[K, V] {
+	var o linkedOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return Linked[K, V]{
+		opts:   o,
+		lookup: make(map[K]*node[K, V]),
+	}
+}
+
+// Entries returns a newly allocated, ordered slice of Entry structs which can be iterated on.
+func (lm *Linked[K, V]) Entries() []Entry[K, V] {
+	lm.m.RLock()
+	defer lm.m.RUnlock()
+	entries := make([]Entry[K, V], 0, len(lm.lookup))
+	for n := lm.head; n != nil; n = n.next {
+		entries = append(entries, n.entry)
+	}
+
+	return entries
+}
+
+// All returns an iterator over the map's key/value pairs in order. It is equivalent to AllCtx with a context that is
+// never cancelled.
+func (lm *Linked[K, V]) All() iter.Seq2[K, V] {
+	return lm.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the map's key/value pairs in order. Iteration stops as soon as ctx is cancelled. The
+// read lock is held until iteration finishes or stops, so the loop body must not mutate the same map.
+func (lm *Linked[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		lm.m.RLock()
+		defer lm.m.RUnlock()
+		for n := lm.head; n != nil; n = n.next {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !yield(n.entry.Key, n.entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Get implements a map lookup. This should semantically be O(1) and equivalent to val, ok := map[key].
+func (lm *Linked[K, V]) Get(key K) (V, bool) {
+	lm.m.RLock()
+	defer lm.m.RUnlock()
+	n, ok := lm.lookup[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return n.entry.Value, true
+}
+
+// Index returns the ordered index associated with the given key. Unlike OrdMap, this requires walking the list and is
+// O(n).
+func (lm *Linked[K, V]) Index(key K) (int, bool) {
+	lm.m.RLock()
+	defer lm.m.RUnlock()
+	target, ok := lm.lookup[key]
+	if !ok {
+		return 0, false
+	}
+
+	idx := 0
+	for n := lm.head; n != target; n = n.next {
+		idx++
+	}
+
+	return idx, true
+}
+
+// Set a key/value pair within the map. New keys are appended to the end of the ordering.
+func (lm *Linked[K, V]) Set(key K, val V) {
+	lm.BulkSet(Entry[K, V]{Key: key, Value: val})
+}
+
+// BulkSet allows for setting many entries at once while only locking the mutex once. In the case of duplicated keys,
+// earlier values in the list will be overwritten.
+func (lm *Linked[K, V]) BulkSet(entries ...Entry[K, V]) {
+	lm.m.Lock()
+	defer lm.m.Unlock()
+	for _, entry := range entries {
+		if n, ok := lm.lookup[entry.Key]; ok {
+			n.entry = entry
+			continue
+		}
+
+		n := lm.alloc()
+		n.entry = entry
+		lm.lookup[entry.Key] = n
+		lm.pushBack(n)
+	}
+}
+
+// Has works the same as Get but does not return the value. It's included for convenience.
+func (lm *Linked[K, V]) Has(key K) bool {
+	lm.m.RLock()
+	_, ok := lm.lookup[key]
+	lm.m.RUnlock()
+	return ok
+}
+
+// Delete a key from the map in O(1).
+func (lm *Linked[K, V]) Delete(key K) {
+	lm.m.Lock()
+	defer lm.m.Unlock()
+	n, ok := lm.lookup[key]
+	if !ok {
+		return
+	}
+
+	lm.unlink(n)
+	delete(lm.lookup, key)
+	lm.release(n)
+}
+
+// Len returns the current length of the map.
+func (lm *Linked[K, V]) Len() int {
+	lm.m.RLock()
+	defer lm.m.RUnlock()
+	return len(lm.lookup)
+}
+
+// MoveToFront moves key to the start of the ordering in O(1). It returns false if key is not present.
+func (lm *Linked[K, V]) MoveToFront(key K) bool {
+	lm.m.Lock()
+	defer lm.m.Unlock()
+	n, ok := lm.lookup[key]
+	if !ok {
+		return false
+	}
+
+	lm.unlink(n)
+	lm.pushFront(n)
+	return true
+}
+
+// MoveToBack moves key to the end of the ordering in O(1). It returns false if key is not present.
+func (lm *Linked[K, V]) MoveToBack(key K) bool {
+	lm.m.Lock()
+	defer lm.m.Unlock()
+	n, ok := lm.lookup[key]
+	if !ok {
+		return false
+	}
+
+	lm.unlink(n)
+	lm.pushBack(n)
+	return true
+}
+
+// MoveBefore moves key so that it immediately precedes mark in O(1). It returns false if either key is not present.
+func (lm *Linked[K, V]) MoveBefore(key, mark K) bool {
+	lm.m.Lock()
+	defer lm.m.Unlock()
+	n, ok := lm.lookup[key]
+	if !ok {
+		return false
+	}
+
+	at, ok := lm.lookup[mark]
+	if !ok {
+		return false
+	}
+
+	if n == at {
+		return true
+	}
+
+	lm.unlink(n)
+	lm.insertAfter(n, at.prev)
+	return true
+}
+
+// MoveAfter moves key so that it immediately follows mark in O(1). It returns false if either key is not present.
+func (lm *Linked[K, V]) MoveAfter(key, mark K) bool {
+	lm.m.Lock()
+	defer lm.m.Unlock()
+	n, ok := lm.lookup[key]
+	if !ok {
+		return false
+	}
+
+	at, ok := lm.lookup[mark]
+	if !ok {
+		return false
+	}
+
+	if n == at {
+		return true
+	}
+
+	lm.unlink(n)
+	lm.insertAfter(n, at)
+	return true
+}
+
+// pushFront links n in as the new head of the list.
+func (lm *Linked[K, V]) pushFront(n *node[K, V]) {
+	lm.insertAfter(n, nil)
+}
+
+// pushBack links n in as the new tail of the list.
+func (lm *Linked[K, V]) pushBack(n *node[K, V]) {
+	lm.insertAfter(n, lm.tail)
+}
+
+// insertAfter links n in immediately after prev. A nil prev inserts n at the head of the list.
+func (lm *Linked[K, V]) insertAfter(n, prev *node[K, V]) {
+	n.prev = prev
+	if prev == nil {
+		n.next = lm.head
+		lm.head = n
+	} else {
+		n.next = prev.next
+		prev.next = n
+	}
+
+	if n.next == nil {
+		lm.tail = n
+	} else {
+		n.next.prev = n
+	}
+}
+
+// unlink removes n from the list without touching the lookup map.
+func (lm *Linked[K, V]) unlink(n *node[K, V]) {
+	if n.prev == nil {
+		lm.head = n.next
+	} else {
+		n.prev.next = n.next
+	}
+
+	if n.next == nil {
+		lm.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+
+	n.prev = nil
+	n.next = nil
+}
+
+// alloc returns a zeroed node, taking it from the freelist or the current slab when slabs are enabled.
+func (lm *Linked[K, V]) alloc() *node[K, V] {
+	if lm.opts.slabSize <= 0 {
+		return &node[K, V]{}
+	}
+
+	if n := lm.free; n != nil {
+		lm.free = n.next
+		n.next = nil
+		return n
+	}
+
+	if len(lm.slab) == 0 {
+		lm.slab = make([]node[K, V], lm.opts.slabSize)
+	}
+
+	n := &lm.slab[0]
+	lm.slab = lm.slab[1:]
+	return n
+}
+
+// release hands an unlinked node back to the freelist when slabs are enabled. The node's entry is cleared so the
+// freelist doesn't keep keys or values alive.
+func (lm *Linked[K, V]) release(n *node[K, V]) {
+	if lm.opts.slabSize <= 0 {
+		return
+	}
+
+	n.entry = Entry[K, V]{}
+	n.next = lm.free
+	lm.free = n
+}
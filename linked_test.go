@@ -0,0 +1,83 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func keys[K comparable, V any](om ordmap.Map[K, V]) []K {
+	var keys []K
+	for key := range om.All() {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func Test_LinkedLifecycle(t *testing.T) {
+	lm := ordmap.NewLinked[string, int]()
+	for i := 0; i < 5; i++ {
+		lm.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	lm.Delete("key 2")
+	lm.Set("key 1", 10)
+
+	if val, ok := lm.Get("key 1"); !ok || val != 10 {
+		t.Fatalf("expected key 1 to be updated to 10, got %d", val)
+	}
+
+	if lm.Has("key 2") || lm.Len() != 4 {
+		t.Fatalf("expected key 2 to be deleted, length is %d", lm.Len())
+	}
+
+	lm.MoveToFront("key 4")
+	lm.MoveToBack("key 0")
+	lm.MoveAfter("key 1", "key 3")
+
+	expected := []string{"key 4", "key 3", "key 1", "key 0"}
+	got := keys[string, int](&lm)
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("expected order %v, got %v", expected, got)
+	}
+
+	if idx, ok := lm.Index("key 1"); !ok || idx != 2 {
+		t.Fatalf("expected key 1 to be at index 2, got %d", idx)
+	}
+
+	lm.MoveBefore("key 0", "key 4")
+	if idx, _ := lm.Index("key 0"); idx != 0 {
+		t.Fatalf("expected key 0 to be moved to the front, got index %d", idx)
+	}
+}
+
+func Test_LinkedSlabs(t *testing.T) {
+	lm := ordmap.NewLinked[string, int](ordmap.WithSlabs(64))
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 1000; i++ {
+			lm.Set(fmt.Sprintf("key %d", i), i+round)
+		}
+
+		for i := 0; i < 1000; i += 2 {
+			lm.Delete(fmt.Sprintf("key %d", i))
+		}
+
+		if lm.Len() != 500 {
+			t.Fatalf("expected 500 entries after round %d, got %d", round, lm.Len())
+		}
+
+		for i := 0; i < 1000; i += 2 {
+			if lm.Has(fmt.Sprintf("key %d", i)) {
+				t.Fatalf("expected key %d to be deleted", i)
+			}
+		}
+
+		for i := 1; i < 1000; i += 2 {
+			if val, ok := lm.Get(fmt.Sprintf("key %d", i)); !ok || val != i+round {
+				t.Fatalf("expected key %d to be %d, got %d", i, i+round, val)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package ordmap
+
+import "time"
+
+// NewLRU returns an OrdMap bounded to maxEntries that behaves as a least-recently-used cache. Get and Set move the entry
+// they touch to the end of the ordering, and inserting a new key beyond the bound evicts the entry at the front, which is
+// always the least recently used one. Use WithOnEvict to be told about evictions. Since Get reorders entries, it takes
+// the write lock rather than the read lock.
+func NewLRU[K comparable, V any](maxEntries int, opts ...Option[K, V]) OrdMap[K, V] {
+	return New(maxEntries, append([]Option[K, V]{withMaxEntries[K, V](maxEntries), WithAccessOrder[K, V]()}, opts...)...)
+}
+
+// WithOnEvict registers a callback invoked with every entry evicted because a bounded OrdMap grew beyond its size or
+// because its TTL expired and it was reaped.
+// Callbacks run after the write lock has been released, so they may safely use the OrdMap.
+func WithOnEvict[K comparable, V any](fn func(K, V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.onEvict = fn
+	}
+}
+
+// withMaxEntries bounds an OrdMap to n live entries by evicting from the front.
+func withMaxEntries[K comparable, V any](n int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.maxEntries = n
+	}
+}
+
+// WithAccessOrder orders an OrdMap by access rather than insertion: Get and Sets that update an existing key move the
+// entry they touch to the end of the ordering, so First is always the least recently used entry and Last the most
+// recent one. Since Get reorders entries, it takes the write lock rather than the read lock.
+func WithAccessOrder[K comparable, V any]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.accessOrder = true
+	}
+}
+
+// WithMoveOnUpdate makes Set on an existing key move the entry to the end of the ordering instead of updating it in
+// place, so the ordering reflects how recently each key was last written. Unlike WithAccessOrder, reads never change
+// the ordering.
+func WithMoveOnUpdate[K comparable, V any]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.moveOnUpdate = true
+	}
+}
+
+// getAndTouch looks up key under the write lock and records the access, moving its entry to the end of the ordering
+// when access ordering is enabled and notifying the eviction policy if there is one.
+func (om *OrdMap[K, V]) getAndTouch(key K) (V, bool) {
+	om.m.Lock()
+	defer om.m.Unlock()
+	idx, ok := om.lookup[key]
+	if !ok || om.expired(key, time.Now()) {
+		var zero V
+		return zero, false
+	}
+
+	if om.opts.policy != nil {
+		om.opts.policy.OnGet(key)
+	}
+
+	if om.opts.accessOrder {
+		idx = om.moveToBack(idx)
+	}
+
+	return om.data[idx].Value, true
+}
+
+// moveToBack moves the entry at idx to the end of the ordering by leaving a tombstone in its place, and returns its
+// new index. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) moveToBack(idx int) int {
+	last := len(om.data) - 1
+	if idx == last {
+		return idx
+	}
+
+	om.unshare()
+	entry := om.data[idx]
+	var zero V
+	om.data[idx].Value = zero
+	if len(om.data) == cap(om.data) {
+		om.metrics.grow()
+	}
+
+	om.lookup[entry.Key] = len(om.data)
+	om.data = append(om.data, entry)
+	om.tombstones++
+	if om.tombstones*2 > len(om.data) {
+		om.sweep()
+	}
+
+	return om.lookup[entry.Key]
+}
+
+// evictOverflow evicts entries until a bounded OrdMap is back within its bound, or until only pinned entries are left
+// to evict. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) evictOverflow() {
+	for om.opts.maxEntries > 0 && len(om.lookup) > om.opts.maxEntries {
+		if om.opts.policy == nil {
+			if !om.evictFront() {
+				return
+			}
+		} else if victim, ok := om.opts.policy.Victim(); ok {
+			om.evict(victim)
+		} else {
+			return
+		}
+	}
+}
+
+// evictFront deletes the first live, unpinned entry and queues it for the eviction callback. It returns false if there
+// is no such entry. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) evictFront() bool {
+	for om.front < len(om.data) && !om.live(om.front) {
+		om.front++
+	}
+
+	idx := om.front
+	for idx < len(om.data) && (!om.live(idx) || om.isPinned(om.data[idx].Key)) {
+		idx++
+	}
+
+	if idx >= len(om.data) {
+		return false
+	}
+
+	om.evict(om.data[idx].Key)
+	return true
+}
+
+// evict deletes key and queues its entry for the eviction callback. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) evict(key K) {
+	idx, ok := om.lookup[key]
+	if !ok {
+		return
+	}
+
+	entry := om.data[idx]
+	om.delete(key)
+	if om.opts.onEvict != nil {
+		om.evicted = append(om.evicted, entry)
+	}
+}
+
+// First returns the first entry in the OrdMap's ordering, or false if it's empty.
+func (om *OrdMap[K, V]) First() (K, V, bool) {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	now := time.Now()
+	for idx := om.front; idx < len(om.data); idx++ {
+		if om.visible(idx, now) {
+			return om.data[idx].Key, om.data[idx].Value, true
+		}
+	}
+
+	var (
+		key K
+		val V
+	)
+	return key, val, false
+}
+
+// Last returns the last entry in the OrdMap's ordering, or false if it's empty.
+func (om *OrdMap[K, V]) Last() (K, V, bool) {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	now := time.Now()
+	for idx := len(om.data) - 1; idx >= 0; idx-- {
+		if om.visible(idx, now) {
+			return om.data[idx].Key, om.data[idx].Value, true
+		}
+	}
+
+	var (
+		key K
+		val V
+	)
+	return key, val, false
+}
@@ -0,0 +1,107 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_LRU(t *testing.T) {
+	var evicted []string
+	var om ordmap.OrdMap[string, int]
+	om = ordmap.NewLRU(3, ordmap.WithOnEvict(func(key string, _ int) {
+		evicted = append(evicted, key)
+		// callbacks run outside of the lock
+		om.Len()
+	}))
+
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+	om.Get("a")
+	om.Set("d", 4)
+	om.Set("c", 30)
+	om.Set("e", 5)
+
+	if len(evicted) != 2 || evicted[0] != "b" || evicted[1] != "a" {
+		t.Fatalf("expected b then a to be evicted, got %v", evicted)
+	}
+
+	expected := []string{"d", "c", "e"}
+	for idx, entry := range om.Entries() {
+		if entry.Key != expected[idx] {
+			t.Fatalf("expected entry #%d to be %s, got %s", idx, expected[idx], entry.Key)
+		}
+	}
+
+	if om.Len() != 3 {
+		t.Fatalf("expected map length to stay bounded at 3, got %d", om.Len())
+	}
+}
+
+func Test_AccessOrder(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithAccessOrder[string, int]())
+	if _, _, ok := om.First(); ok {
+		t.Fatal("expected no first entry in an empty map")
+	}
+
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+	om.Get("a")
+	om.Has("b")
+
+	if key, _, _ := om.First(); key != "b" {
+		t.Fatalf("expected b to be least recently used, got %s", key)
+	}
+
+	if key, val, _ := om.Last(); key != "a" || val != 1 {
+		t.Fatalf("expected a to be most recently used, got %s", key)
+	}
+
+	om.Set("b", 20)
+	om.Delete("c")
+	if key, _, _ := om.First(); key != "a" {
+		t.Fatalf("expected a to be least recently used, got %s", key)
+	}
+}
+
+func Test_MoveOnUpdate(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithMoveOnUpdate[string, int]())
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+	om.Set("a", 10)
+	om.Get("b")
+
+	expected := []ordmap.Entry[string, int]{{Key: "b", Value: 2}, {Key: "c", Value: 3}, {Key: "a", Value: 10}}
+	for idx, entry := range om.Entries() {
+		if entry != expected[idx] {
+			t.Fatalf("expected entry #%d to be %+v, got %+v", idx, expected[idx], entry)
+		}
+	}
+}
+
+func Test_LRUSwapDeleteResetsFront(t *testing.T) {
+	om := ordmap.NewLRU[string, int](3)
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		om.Set(key, i)
+	}
+
+	om.SwapDelete("e")
+	om.SwapDelete("d")
+	om.SwapDelete("c")
+	om.Set("x", 10)
+	if key, _, ok := om.First(); !ok || key != "x" || om.Len() != 1 {
+		t.Fatalf("expected x to be the only entry, got %s, %t with length %d", key, ok, om.Len())
+	}
+
+	if err := om.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	om.Clear(false)
+	if om.Len() != 0 || om.Has("x") {
+		t.Fatal("expected clear to remove x")
+	}
+}
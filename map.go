@@ -0,0 +1,40 @@
+package ordmap
+
+import (
+	"context"
+	"iter"
+)
+
+// ReadOnly is the read half of Map. Accepting a ReadOnly instead of a Map documents that a function won't mutate the
+// map it's given, and a Frozen map only implements ReadOnly, so it can't be mutated at all.
+type ReadOnly[K comparable, V any] interface {
+	Entries() []Entry[K, V]
+	All() iter.Seq2[K, V]
+	AllCtx(ctx context.Context) iter.Seq2[K, V]
+	Get(key K) (V, bool)
+	Index(key K) (int, bool)
+	Has(key K) bool
+	Len() int
+}
+
+// Map is the API shared by every ordered map backing in this package. It allows choosing a backing with the
+// trade-offs that suit a use case (see New and NewLinked) without changing the code that uses it.
+type Map[K comparable, V any] interface {
+	ReadOnly[K, V]
+	Set(key K, val V)
+	BulkSet(entries ...Entry[K, V])
+	Delete(key K)
+}
+
+var (
+	_ Map[string, int] = (*OrdMap[string, int])(nil)
+	_ Map[string, int] = (*Linked[string, int])(nil)
+	_ Map[string, int] = (*Sharded[string, int])(nil)
+	_ Map[string, int] = (*CopyOnWrite[string, int])(nil)
+	_ Map[string, int] = (*ReadMostly[string, int])(nil)
+	_ Map[string, int] = (*SortedMap[string, int])(nil)
+	_ Map[string, int] = (*PriorityOrdMap[string, int])(nil)
+
+	_ ReadOnly[string, int] = Frozen[string, int]{}
+	_ ReadOnly[string, int] = (*Mmap[string, int])(nil)
+)
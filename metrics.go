@@ -0,0 +1,96 @@
+package ordmap
+
+import "sync/atomic"
+
+// Metrics counts the operations performed on an OrdMap created with WithMetrics.
+type Metrics struct {
+	// Hits and Misses count the calls to Get that did and didn't find their key.
+	Hits   uint64
+	Misses uint64
+	// Sets counts every key/value pair set, whether the key was new or not.
+	Sets uint64
+	// Deletes counts every key removed, including by eviction and expiry.
+	Deletes uint64
+	// Grows counts how many times the data slice had to be reallocated to make room for more entries.
+	Grows uint64
+}
+
+// HitRatio returns the fraction of calls to Get that found their key, or zero if Get hasn't been called.
+func (m Metrics) HitRatio() float64 {
+	if m.Hits+m.Misses == 0 {
+		return 0
+	}
+
+	return float64(m.Hits) / float64(m.Hits+m.Misses)
+}
+
+// metrics holds the live counters behind Metrics. They're atomic since Get only holds the read lock. Every method is
+// a no-op on a nil *metrics, so call sites don't have to check whether metrics are enabled.
+type metrics struct {
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	sets    atomic.Uint64
+	deletes atomic.Uint64
+	grows   atomic.Uint64
+}
+
+// WithMetrics enables counting Get hits and misses, sets, deletes, and growth of the OrdMap, retrievable with Metrics.
+// Counting costs an atomic add per operation.
+func WithMetrics[K comparable, V any]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.metrics = true
+	}
+}
+
+// Metrics returns the OrdMap's operation counts so far. It returns zero counts unless the OrdMap was created with
+// WithMetrics. The counters are read individually, so they may be mid-update relative to each other.
+func (om *OrdMap[K, V]) Metrics() Metrics {
+	m := om.metrics
+	if m == nil {
+		return Metrics{}
+	}
+
+	return Metrics{
+		Hits:    m.hits.Load(),
+		Misses:  m.misses.Load(),
+		Sets:    m.sets.Load(),
+		Deletes: m.deletes.Load(),
+		Grows:   m.grows.Load(),
+	}
+}
+
+// get counts a call to Get.
+func (m *metrics) get(found bool) {
+	if m == nil {
+		return
+	}
+
+	if found {
+		m.hits.Add(1)
+	} else {
+		m.misses.Add(1)
+	}
+}
+
+// change counts a set or delete.
+func (m *metrics) change(op Op) {
+	if m == nil {
+		return
+	}
+
+	switch op {
+	case OpSet:
+		m.sets.Add(1)
+	case OpDelete:
+		m.deletes.Add(1)
+	}
+}
+
+// grow counts a reallocation of the data slice.
+func (m *metrics) grow() {
+	if m == nil {
+		return
+	}
+
+	m.grows.Add(1)
+}
@@ -0,0 +1,36 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Metrics(t *testing.T) {
+	om := ordmap.New(1, ordmap.WithMetrics[string, int]())
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("a", 3)
+	om.Delete("b")
+	om.Delete("missing")
+	om.Get("a")
+	om.Get("a")
+	om.Get("b")
+	om.Grow(100)
+
+	expected := ordmap.Metrics{Hits: 2, Misses: 1, Sets: 3, Deletes: 1, Grows: 2}
+	if got := om.Metrics(); got != expected {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+
+	if ratio := om.Metrics().HitRatio(); ratio < 0.66 || ratio > 0.67 {
+		t.Fatalf("expected a hit ratio of 2/3, got %f", ratio)
+	}
+
+	plain := ordmap.New[string, int](0)
+	plain.Set("a", 1)
+	plain.Get("a")
+	if plain.Metrics() != (ordmap.Metrics{}) {
+		t.Fatal("expected no metrics without WithMetrics")
+	}
+}
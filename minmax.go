@@ -0,0 +1,49 @@
+package ordmap
+
+import (
+	"cmp"
+	"time"
+)
+
+// MinKey returns the entry of om with the smallest key, or false if om is empty. It scans every entry under a single
+// read lock, so it's O(n).
+func MinKey[K cmp.Ordered, V any](om *OrdMap[K, V]) (Entry[K, V], bool) {
+	return extremeKey(om, cmp.Compare[K], -1)
+}
+
+// MaxKey returns the entry of om with the largest key, or false if om is empty. Like MinKey, it's O(n).
+func MaxKey[K cmp.Ordered, V any](om *OrdMap[K, V]) (Entry[K, V], bool) {
+	return extremeKey(om, cmp.Compare[K], 1)
+}
+
+// MinKeyFunc is like MinKey, but orders keys with compare, which returns a negative number when a < b, a positive
+// number when a > b, and zero when they're equal.
+func MinKeyFunc[K comparable, V any](om *OrdMap[K, V], compare func(a, b K) int) (Entry[K, V], bool) {
+	return extremeKey(om, compare, -1)
+}
+
+// MaxKeyFunc is like MaxKey, but orders keys with compare, in the same way as MinKeyFunc.
+func MaxKeyFunc[K comparable, V any](om *OrdMap[K, V], compare func(a, b K) int) (Entry[K, V], bool) {
+	return extremeKey(om, compare, 1)
+}
+
+// extremeKey returns the entry whose key compares furthest in the direction of sign. Ties go to the entry earliest in
+// om's order.
+func extremeKey[K comparable, V any](om *OrdMap[K, V], compare func(a, b K) int, sign int) (Entry[K, V], bool) {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	var best Entry[K, V]
+	found := false
+	now := time.Now()
+	for idx := om.front; idx < len(om.data); idx++ {
+		if !om.visible(idx, now) {
+			continue
+		}
+
+		if !found || compare(om.data[idx].Key, best.Key)*sign > 0 {
+			best, found = om.data[idx], true
+		}
+	}
+
+	return best, found
+}
@@ -0,0 +1,44 @@
+package ordmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_MinMaxKey(t *testing.T) {
+	om := ordmap.New[int, string](0)
+	if _, ok := ordmap.MinKey(&om); ok {
+		t.Fatal("expected no min key in an empty map")
+	}
+
+	for _, key := range []int{5, 3, 9, 1, 7} {
+		om.Set(key, strings.Repeat("x", key))
+	}
+
+	om.Delete(1)
+	if min, ok := ordmap.MinKey(&om); !ok || min.Key != 3 || min.Value != "xxx" {
+		t.Fatalf("expected min key 3, got %v", min)
+	}
+
+	if max, ok := ordmap.MaxKey(&om); !ok || max.Key != 9 {
+		t.Fatalf("expected max key 9, got %v", max)
+	}
+
+	names := ordmap.New[string, int](0)
+	names.Set("bob", 1)
+	names.Set("Alice", 2)
+	names.Set("carol", 3)
+	fold := func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+
+	if min, _ := ordmap.MinKeyFunc(&names, fold); min.Key != "Alice" {
+		t.Fatalf("expected case insensitive min key Alice, got %s", min.Key)
+	}
+
+	if max, _ := ordmap.MaxKeyFunc(&names, fold); max.Key != "carol" {
+		t.Fatalf("expected case insensitive max key carol, got %s", max.Key)
+	}
+}
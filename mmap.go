@@ -0,0 +1,321 @@
+package ordmap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// The mmap layout is the magic, every record in order, the offsets of the records, a hash table of record indices, and
+// a fixed size footer. Each record is a length-prefixed key followed by a length-prefixed value, as in WriteTo. The
+// hash table uses linear probing over FNV-1a hashes of the encoded keys, and holds each record's index plus one so
+// that zero marks an empty slot. Everything is little endian, and the tables are written after the records so that a
+// builder can stream records without knowing how many there will be.
+const (
+	mmapMagic = "ordmmap1"
+	// mmapFooterSize is the size of the footer holding the record count, the hash table size, the offset of the record
+	// offsets, and the magic again.
+	mmapFooterSize = 3*8 + len(mmapMagic)
+)
+
+// An MmapBuilder writes entries in the layout read by OpenMmap. Entries are streamed to the underlying writer as
+// they're added, so only 16 bytes per entry are kept in memory until Close writes the index. Keys must be unique;
+// OpenMmap finds the first of any duplicates.
+type MmapBuilder[K comparable, V any] struct {
+	w          *bufio.Writer
+	cw         *countingWriter
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+	offsets    []uint64
+	hashes     []uint64
+	buf        []byte
+}
+
+// NewMmapBuilder returns an MmapBuilder writing to w. Only the codec options WithKeyCodec and WithValueCodec apply to
+// the builder, and the same ones have to be passed to OpenMmap.
+func NewMmapBuilder[K comparable, V any](w io.Writer, opts ...Option[K, V]) (*MmapBuilder[K, V], error) {
+	var o options[K, V]
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	keyCodec, valueCodec := o.codecs()
+	cw := &countingWriter{w: w}
+	b := &MmapBuilder[K, V]{w: bufio.NewWriter(cw), cw: cw, keyCodec: keyCodec, valueCodec: valueCodec}
+	if _, err := b.w.WriteString(mmapMagic); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Add appends an entry.
+func (b *MmapBuilder[K, V]) Add(key K, val V) error {
+	var err error
+	if b.buf, err = appendField(b.buf[:0], b.keyCodec, key); err != nil {
+		return fmt.Errorf("encoding key: %w", err)
+	}
+
+	field, _, _ := splitField(b.buf)
+	b.hashes = append(b.hashes, fnv1a(field))
+	if b.buf, err = appendField(b.buf, b.valueCodec, val); err != nil {
+		return fmt.Errorf("encoding value: %w", err)
+	}
+
+	b.offsets = append(b.offsets, uint64(b.cw.n)+uint64(b.w.Buffered()))
+	_, err = b.w.Write(b.buf)
+	return err
+}
+
+// Close writes the index and footer and flushes everything to the underlying writer. It doesn't close the writer.
+func (b *MmapBuilder[K, V]) Close() error {
+	start := uint64(b.cw.n) + uint64(b.w.Buffered())
+	buf := b.buf[:0]
+	for _, offset := range b.offsets {
+		buf = binary.LittleEndian.AppendUint64(buf, offset)
+	}
+
+	// keep the hash table at most half full so probes stay short
+	slots := uint64(1)
+	for slots < uint64(len(b.offsets))*2 {
+		slots *= 2
+	}
+
+	table := make([]uint64, slots)
+	for idx, hash := range b.hashes {
+		slot := hash & (slots - 1)
+		for table[slot] != 0 {
+			slot = (slot + 1) & (slots - 1)
+		}
+
+		table[slot] = uint64(idx) + 1
+	}
+
+	for _, entry := range table {
+		buf = binary.LittleEndian.AppendUint64(buf, entry)
+	}
+
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(b.offsets)))
+	buf = binary.LittleEndian.AppendUint64(buf, slots)
+	buf = binary.LittleEndian.AppendUint64(buf, start)
+	buf = append(buf, mmapMagic...)
+	if _, err := b.w.Write(buf); err != nil {
+		return err
+	}
+
+	return b.w.Flush()
+}
+
+// An Mmap is a read-only ordered map served directly from a memory-mapped file written by an MmapBuilder. Opening one
+// costs O(1) regardless of its size, and pages are only loaded as they're touched and can be shared by every process
+// mapping the same file. Keys and values are decoded on every access. An Mmap is safe for concurrent use, but must not
+// be used after Close. On platforms without mmap the whole file is read into memory instead.
+type Mmap[K comparable, V any] struct {
+	data       []byte
+	count      uint64
+	slots      uint64
+	offsets    []byte
+	table      []byte
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+	close      func() error
+}
+
+// OpenMmap maps the file at path, which must have been written by an MmapBuilder with the same codec options.
+func OpenMmap[K comparable, V any](path string, opts ...Option[K, V]) (*Mmap[K, V], error) {
+	data, closer, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var o options[K, V]
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	m := &Mmap[K, V]{data: data, close: closer}
+	m.keyCodec, m.valueCodec = o.codecs()
+	if err := m.parse(); err != nil {
+		closer()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// parse validates the layout and locates the tables.
+func (m *Mmap[K, V]) parse() error {
+	size := uint64(len(m.data))
+	if size < uint64(len(mmapMagic)+mmapFooterSize) || string(m.data[:len(mmapMagic)]) != mmapMagic ||
+		string(m.data[size-uint64(len(mmapMagic)):]) != mmapMagic {
+		return fmt.Errorf("%w: bad magic", ErrInvalidSnapshot)
+	}
+
+	footer := m.data[size-uint64(mmapFooterSize):]
+	m.count = binary.LittleEndian.Uint64(footer)
+	m.slots = binary.LittleEndian.Uint64(footer[8:])
+	start := binary.LittleEndian.Uint64(footer[16:])
+	end := size - uint64(mmapFooterSize)
+	if m.slots == 0 || m.slots&(m.slots-1) != 0 || start > end || (end-start)%8 != 0 {
+		return fmt.Errorf("%w: bad footer", ErrInvalidSnapshot)
+	}
+
+	// the tables have to fill exactly the words between start and end, checked by subtraction so that crafted counts
+	// can't overflow
+	words := (end - start) / 8
+	if m.count > words || words-m.count != m.slots {
+		return fmt.Errorf("%w: bad footer", ErrInvalidSnapshot)
+	}
+
+	m.offsets = m.data[start : start+8*m.count]
+	m.table = m.data[start+8*m.count : end]
+	return nil
+}
+
+// Close unmaps the file.
+func (m *Mmap[K, V]) Close() error {
+	return m.close()
+}
+
+// record returns the encoded key and value of the record at idx.
+func (m *Mmap[K, V]) record(idx uint64) ([]byte, []byte, error) {
+	offset := binary.LittleEndian.Uint64(m.offsets[8*idx:])
+	if offset >= uint64(len(m.data)) {
+		return nil, nil, fmt.Errorf("%w: record out of range", ErrInvalidSnapshot)
+	}
+
+	key, rest, err := splitField(m.data[offset:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	val, _, err := splitField(rest)
+	return key, val, err
+}
+
+// find returns the index of the record for key. Probing gives up after visiting every slot once, so a corrupt table
+// without an empty slot can't make it loop forever.
+func (m *Mmap[K, V]) find(key K) (uint64, bool) {
+	encoded, err := m.keyCodec.Append(nil, key)
+	if err != nil {
+		return 0, false
+	}
+
+	slot := fnv1a(encoded) & (m.slots - 1)
+	for range m.slots {
+		entry := binary.LittleEndian.Uint64(m.table[8*slot:])
+		if entry == 0 || entry > m.count {
+			return 0, false
+		}
+
+		stored, _, err := m.record(entry - 1)
+		if err == nil && bytes.Equal(stored, encoded) {
+			return entry - 1, true
+		}
+
+		slot = (slot + 1) & (m.slots - 1)
+	}
+
+	return 0, false
+}
+
+// Get returns the value stored at key and whether it was found. Values that fail to decode are reported as missing.
+func (m *Mmap[K, V]) Get(key K) (V, bool) {
+	var zero V
+	idx, ok := m.find(key)
+	if !ok {
+		return zero, false
+	}
+
+	_, encoded, err := m.record(idx)
+	if err != nil {
+		return zero, false
+	}
+
+	val, err := m.valueCodec.Decode(encoded)
+	if err != nil {
+		return zero, false
+	}
+
+	return val, true
+}
+
+// Index returns the position of key in the file's order.
+func (m *Mmap[K, V]) Index(key K) (int, bool) {
+	idx, ok := m.find(key)
+	return int(idx), ok
+}
+
+// Has returns whether key is present.
+func (m *Mmap[K, V]) Has(key K) bool {
+	_, ok := m.find(key)
+	return ok
+}
+
+// Len returns the number of entries.
+func (m *Mmap[K, V]) Len() int {
+	return int(m.count)
+}
+
+// Entries decodes every entry into a newly allocated, ordered slice. This loads the whole map onto the heap, so All is
+// usually the better choice. Entries that fail to decode are skipped.
+func (m *Mmap[K, V]) Entries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, m.count)
+	for key, val := range m.All() {
+		entries = append(entries, Entry[K, V]{Key: key, Value: val})
+	}
+
+	return entries
+}
+
+// All returns an iterator over the key/value pairs in order.
+func (m *Mmap[K, V]) All() iter.Seq2[K, V] {
+	return m.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the key/value pairs in order, stopping early if ctx is cancelled. Entries that fail to
+// decode are skipped.
+func (m *Mmap[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for idx := range m.count {
+			if ctx.Err() != nil {
+				return
+			}
+
+			encodedKey, encodedVal, err := m.record(idx)
+			if err != nil {
+				continue
+			}
+
+			key, err := m.keyCodec.Decode(encodedKey)
+			if err != nil {
+				continue
+			}
+
+			val, err := m.valueCodec.Decode(encodedVal)
+			if err != nil {
+				continue
+			}
+
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// fnv1a returns the 64 bit FNV-1a hash of data. It's used instead of maphash because the hashes are stored in files
+// and have to be the same in every process.
+func fnv1a(data []byte) uint64 {
+	hash := uint64(14695981039346656037)
+	for _, b := range data {
+		hash ^= uint64(b)
+		hash *= 1099511628211
+	}
+
+	return hash
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package ordmap
+
+import "os"
+
+// mapFile reads the whole file at path, since memory mapping isn't supported on this platform.
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,117 @@
+package ordmap_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Mmap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("unexpected error creating file: %v", err)
+	}
+
+	b, err := ordmap.NewMmapBuilder[string, int](f)
+	if err != nil {
+		t.Fatalf("unexpected error creating builder: %v", err)
+	}
+
+	om := ordmap.New[string, int](0)
+	for i := range 1000 {
+		key := fmt.Sprintf("key %d", (i*7919)%1000)
+		om.Set(key, i)
+		if err := b.Add(key, i); err != nil {
+			t.Fatalf("unexpected error adding entry: %v", err)
+		}
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatalf("unexpected error closing builder: %v", err)
+	}
+	f.Close()
+
+	m, err := ordmap.OpenMmap[string, int](path)
+	if err != nil {
+		t.Fatalf("unexpected error opening mmap: %v", err)
+	}
+	defer m.Close()
+
+	if m.Len() != om.Len() {
+		t.Fatalf("expected %d entries, got %d", om.Len(), m.Len())
+	}
+
+	for idx, entry := range om.Entries() {
+		val, ok := m.Get(entry.Key)
+		if !ok || val != entry.Value {
+			t.Fatalf("expected %q to be %d, got %d, %t", entry.Key, entry.Value, val, ok)
+		}
+
+		if got, _ := m.Index(entry.Key); got != idx {
+			t.Fatalf("expected %q at index %d, got %d", entry.Key, idx, got)
+		}
+	}
+
+	if m.Has("missing") {
+		t.Fatal("expected missing key to be absent")
+	}
+
+	if got, want := fmt.Sprint(m.Entries()), fmt.Sprint(om.Entries()); got != want {
+		t.Fatal("expected iteration to follow insertion order")
+	}
+
+	if err := os.WriteFile(path, []byte("not a map"), 0o644); err != nil {
+		t.Fatalf("unexpected error overwriting file: %v", err)
+	}
+
+	if _, err := ordmap.OpenMmap[string, int](path); err == nil {
+		t.Fatal("expected opening an invalid file to fail")
+	}
+}
+
+func Test_MmapCorruptFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	f, _ := os.Create(path)
+	b, _ := ordmap.NewMmapBuilder[string, int](f)
+	b.Add("a", 1)
+	b.Add("b", 2)
+	b.Close()
+	f.Close()
+
+	valid, _ := os.ReadFile(path)
+	footer := len(valid) - 32
+	count, slots := binary.LittleEndian.Uint64(valid[footer:]), binary.LittleEndian.Uint64(valid[footer+8:])
+	words := count + slots
+
+	// a count and table size that only add up to the right number of words by overflowing
+	data := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint64(data[footer:], words+1<<63)
+	binary.LittleEndian.PutUint64(data[footer+8:], 1<<63)
+	os.WriteFile(path, data, 0o644)
+	if _, err := ordmap.OpenMmap[string, int](path); !errors.Is(err, ordmap.ErrInvalidSnapshot) {
+		t.Fatalf("expected an overflowing footer to be rejected, got %v", err)
+	}
+
+	// a hash table without any empty slots
+	data = append([]byte(nil), valid...)
+	for slot := range slots {
+		binary.LittleEndian.PutUint64(data[footer-8*int(slot+1):], 1)
+	}
+
+	os.WriteFile(path, data, 0o644)
+	m, err := ordmap.OpenMmap[string, int](path)
+	if err != nil {
+		t.Fatalf("unexpected error opening mmap: %v", err)
+	}
+	defer m.Close()
+
+	if m.Has("missing") {
+		t.Fatal("expected missing key to be absent")
+	}
+}
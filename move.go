@@ -0,0 +1,121 @@
+package ordmap
+
+// MoveToFront moves key to the start of the ordering. It returns false if key is not present. Moving entries anywhere
+// but the back is O(n) for an OrdMap; use a Linked map if entries are reordered constantly.
+func (om *OrdMap[K, V]) MoveToFront(key K) bool {
+	om.m.Lock()
+	defer om.m.Unlock()
+	return om.moveToFront(key)
+}
+
+// moveToFront moves key to the start of the ordering. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) moveToFront(key K) bool {
+	key = om.normalize(key)
+	if _, ok := om.lookup[key]; !ok {
+		return false
+	}
+
+	om.moveTo(key, 0)
+	return true
+}
+
+// MoveToBack moves key to the end of the ordering in amortized O(1). It returns false if key is not present.
+func (om *OrdMap[K, V]) MoveToBack(key K) bool {
+	om.m.Lock()
+	defer om.m.Unlock()
+	key = om.normalize(key)
+	idx, ok := om.lookup[key]
+	if !ok {
+		return false
+	}
+
+	om.moveToBack(idx)
+	om.recordMove(key)
+	return true
+}
+
+// MoveAfter moves key so that it immediately follows mark. It returns false if either key is not present. This is
+// O(n).
+func (om *OrdMap[K, V]) MoveAfter(key, mark K) bool {
+	return om.moveRelative(key, mark, true)
+}
+
+// MoveBefore moves key so that it immediately precedes mark. It returns false if either key is not present. This is
+// O(n).
+func (om *OrdMap[K, V]) MoveBefore(key, mark K) bool {
+	return om.moveRelative(key, mark, false)
+}
+
+// moveRelative moves key next to mark, on the side given by after.
+func (om *OrdMap[K, V]) moveRelative(key, mark K, after bool) bool {
+	om.m.Lock()
+	defer om.m.Unlock()
+	return om.moveNextTo(key, mark, after)
+}
+
+// moveNextTo moves key next to mark, on the side given by after. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) moveNextTo(key, mark K, after bool) bool {
+	key, mark = om.normalize(key), om.normalize(mark)
+	_, ok := om.lookup[key]
+	if !ok {
+		return false
+	}
+
+	if _, ok := om.lookup[mark]; !ok {
+		return false
+	}
+
+	if key == mark {
+		return true
+	}
+
+	om.sweep()
+	from, to := om.lookup[key], om.lookup[mark]
+	// once key is taken out of its slot, everything behind it shifts forward by one
+	if from < to {
+		to--
+	}
+
+	if after {
+		to++
+	}
+
+	om.moveTo(key, to)
+	return true
+}
+
+// moveTo moves key to index to of the live ordering and records the move. It's O(n) since every entry between the
+// old and new positions shifts by one. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) moveTo(key K, to int) {
+	om.sweep()
+	om.unshare()
+	from := om.lookup[key]
+	entry := om.data[from]
+	if from < to {
+		copy(om.data[from:to], om.data[from+1:to+1])
+	} else {
+		copy(om.data[to+1:from+1], om.data[to:from])
+	}
+
+	om.data[to] = entry
+	for idx := min(from, to); idx <= max(from, to); idx++ {
+		om.lookup[om.data[idx].Key] = idx
+	}
+
+	om.recordMove(key)
+}
+
+// recordMove records key's move in the change log, along with the key now in front of it. The write lock must be held
+// by the caller.
+func (om *OrdMap[K, V]) recordMove(key K) {
+	idx := om.lookup[key]
+	change := Change[K, V]{Op: OpMove, Key: key, Value: om.data[idx].Value}
+	for prev := idx - 1; prev >= 0; prev-- {
+		if om.live(prev) {
+			change.Prev, change.HasPrev = om.data[prev].Key, true
+			break
+		}
+	}
+
+	om.record(change)
+}
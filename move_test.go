@@ -0,0 +1,48 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Move(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		om.Set(key, i)
+	}
+
+	om.Delete("c")
+	steps := []struct {
+		move   func() bool
+		expect string
+	}{
+		{func() bool { return om.MoveToFront("d") }, "[d a b e]"},
+		{func() bool { return om.MoveToBack("a") }, "[d b e a]"},
+		{func() bool { return om.MoveAfter("d", "e") }, "[b e d a]"},
+		{func() bool { return om.MoveBefore("a", "b") }, "[a b e d]"},
+		{func() bool { return om.MoveBefore("e", "b") }, "[a e b d]"},
+		{func() bool { return om.MoveAfter("a", "d") }, "[e b d a]"},
+	}
+
+	for _, step := range steps {
+		if !step.move() {
+			t.Fatal("expected move of a present key to succeed")
+		}
+
+		if got := fmt.Sprint(keys[string, int](&om)); got != step.expect {
+			t.Fatalf("expected %s, got %s", step.expect, got)
+		}
+	}
+
+	for key, expected := range map[string]int{"a": 3, "e": 0} {
+		if idx, _ := om.Index(key); idx != expected {
+			t.Fatalf("expected %q at index %d, got %d", key, expected, idx)
+		}
+	}
+
+	if om.MoveToFront("c") || om.MoveAfter("a", "c") || om.MoveBefore("c", "a") {
+		t.Fatal("expected moves involving a missing key to fail")
+	}
+}
@@ -0,0 +1,192 @@
+package ordmap
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// An OrdMultiMap is a concurrency safe ordered map where a key can hold multiple values, such as HTTP headers or event
+// logs. Every key/value pair keeps its own position in the overall insertion order, so pairs for different keys can
+// be interleaved. Pairs are stored in a linked list, so removing them is O(1) per pair.
+type OrdMultiMap[K comparable, V comparable] struct {
+	m sync.RWMutex
+
+	// list only uses the linked list half of Linked. Keys map to all of their nodes through lookup instead.
+	list   Linked[K, V]
+	lookup map[K][]*node[K, V]
+	size   int
+}
+
+// NewMulti returns a new, empty OrdMultiMap.
+func NewMulti[K comparable, V comparable]() OrdMultiMap[K, V] {
+	return OrdMultiMap[K, V]{
+		lookup: make(map[K][]*node[K, V]),
+	}
+}
+
+// Entries returns a newly allocated, ordered slice of every key/value pair.
+func (mm *OrdMultiMap[K, V]) Entries() []Entry[K, V] {
+	mm.m.RLock()
+	defer mm.m.RUnlock()
+	entries := make([]Entry[K, V], 0, mm.size)
+	for n := mm.list.head; n != nil; n = n.next {
+		entries = append(entries, n.entry)
+	}
+
+	return entries
+}
+
+// All returns an iterator over every key/value pair in order. It is equivalent to AllCtx with a context that is never
+// cancelled.
+func (mm *OrdMultiMap[K, V]) All() iter.Seq2[K, V] {
+	return mm.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over every key/value pair in order. A key is yielded once for each of its values.
+// Iteration stops as soon as ctx is cancelled. The read lock is held until iteration finishes or stops, so the loop
+// body must not mutate the same map.
+func (mm *OrdMultiMap[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		mm.m.RLock()
+		defer mm.m.RUnlock()
+		for n := mm.list.head; n != nil; n = n.next {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !yield(n.entry.Key, n.entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Add appends a key/value pair to the end of the ordering, keeping any values key already holds.
+func (mm *OrdMultiMap[K, V]) Add(key K, val V) {
+	mm.m.Lock()
+	defer mm.m.Unlock()
+	n := &node[K, V]{entry: Entry[K, V]{Key: key, Value: val}}
+	mm.list.pushBack(n)
+	mm.lookup[key] = append(mm.lookup[key], n)
+	mm.size++
+}
+
+// Set replaces every value held by key with val. If key already exists, the pair keeps the position of its first
+// value. Otherwise it's appended to the end of the ordering.
+func (mm *OrdMultiMap[K, V]) Set(key K, val V) {
+	mm.m.Lock()
+	defer mm.m.Unlock()
+	nodes, ok := mm.lookup[key]
+	if !ok {
+		n := &node[K, V]{entry: Entry[K, V]{Key: key, Value: val}}
+		mm.list.pushBack(n)
+		mm.lookup[key] = []*node[K, V]{n}
+		mm.size++
+		return
+	}
+
+	nodes[0].entry.Value = val
+	for _, n := range nodes[1:] {
+		mm.list.unlink(n)
+	}
+
+	mm.size -= len(nodes) - 1
+	mm.lookup[key] = nodes[:1:1]
+}
+
+// Get returns the first value held by key.
+func (mm *OrdMultiMap[K, V]) Get(key K) (V, bool) {
+	mm.m.RLock()
+	defer mm.m.RUnlock()
+	nodes, ok := mm.lookup[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return nodes[0].entry.Value, true
+}
+
+// GetAll returns a newly allocated slice of every value held by key, in order.
+func (mm *OrdMultiMap[K, V]) GetAll(key K) []V {
+	mm.m.RLock()
+	defer mm.m.RUnlock()
+	nodes := mm.lookup[key]
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	vals := make([]V, len(nodes))
+	for idx, n := range nodes {
+		vals[idx] = n.entry.Value
+	}
+
+	return vals
+}
+
+// Has reports whether key holds at least one value.
+func (mm *OrdMultiMap[K, V]) Has(key K) bool {
+	mm.m.RLock()
+	_, ok := mm.lookup[key]
+	mm.m.RUnlock()
+	return ok
+}
+
+// Delete removes key along with every value it holds.
+func (mm *OrdMultiMap[K, V]) Delete(key K) {
+	mm.m.Lock()
+	defer mm.m.Unlock()
+	nodes := mm.lookup[key]
+	for _, n := range nodes {
+		mm.list.unlink(n)
+	}
+
+	mm.size -= len(nodes)
+	delete(mm.lookup, key)
+}
+
+// DeleteValue removes every pair matching both key and val, and returns how many were removed. The key itself is
+// removed once it holds no more values.
+func (mm *OrdMultiMap[K, V]) DeleteValue(key K, val V) int {
+	mm.m.Lock()
+	defer mm.m.Unlock()
+	nodes, ok := mm.lookup[key]
+	if !ok {
+		return 0
+	}
+
+	kept := slices.DeleteFunc(nodes, func(n *node[K, V]) bool {
+		if n.entry.Value != val {
+			return false
+		}
+
+		mm.list.unlink(n)
+		return true
+	})
+
+	removed := len(nodes) - len(kept)
+	mm.size -= removed
+	if len(kept) == 0 {
+		delete(mm.lookup, key)
+	} else {
+		mm.lookup[key] = kept
+	}
+
+	return removed
+}
+
+// Len returns the total number of key/value pairs.
+func (mm *OrdMultiMap[K, V]) Len() int {
+	mm.m.RLock()
+	defer mm.m.RUnlock()
+	return mm.size
+}
+
+// KeyLen returns the number of distinct keys.
+func (mm *OrdMultiMap[K, V]) KeyLen() int {
+	mm.m.RLock()
+	defer mm.m.RUnlock()
+	return len(mm.lookup)
+}
@@ -0,0 +1,55 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_MultiMap(t *testing.T) {
+	mm := ordmap.NewMulti[string, string]()
+	mm.Add("Accept", "text/html")
+	mm.Add("Cookie", "a=1")
+	mm.Add("Accept", "application/json")
+	mm.Add("Cookie", "b=2")
+	mm.Add("Cookie", "a=1")
+
+	if vals := mm.GetAll("Accept"); fmt.Sprint(vals) != "[text/html application/json]" {
+		t.Fatalf("unexpected values for Accept: %v", vals)
+	}
+
+	if val, _ := mm.Get("Cookie"); val != "a=1" {
+		t.Fatalf("expected first Cookie value to be a=1, got %s", val)
+	}
+
+	if removed := mm.DeleteValue("Cookie", "a=1"); removed != 2 {
+		t.Fatalf("expected to remove 2 cookies, removed %d", removed)
+	}
+
+	mm.Set("Accept", "*/*")
+	mm.Add("Host", "example.com")
+
+	expected := []ordmap.Entry[string, string]{
+		{Key: "Accept", Value: "*/*"},
+		{Key: "Cookie", Value: "b=2"},
+		{Key: "Host", Value: "example.com"},
+	}
+
+	entries := mm.Entries()
+	if len(entries) != len(expected) || mm.Len() != len(expected) || mm.KeyLen() != 3 {
+		t.Fatalf("expected %d pairs, got %v", len(expected), entries)
+	}
+
+	for idx, entry := range entries {
+		if entry != expected[idx] {
+			t.Fatalf("expected pair #%d to be %+v, got %+v", idx, expected[idx], entry)
+		}
+	}
+
+	mm.Delete("Cookie")
+	mm.DeleteValue("Host", "example.com")
+	if mm.Has("Cookie") || mm.Has("Host") || mm.Len() != 1 {
+		t.Fatalf("expected only Accept to remain, got %v", mm.Entries())
+	}
+}
@@ -0,0 +1,20 @@
+package ordmap
+
+// WithKeyNormalizer applies fn to every key an OrdMap is given, both when storing and when looking up, so that keys
+// which normalize to the same value are treated as the same key. For example, strings.ToLower gives case-insensitive
+// keys. The normalized form is what gets stored and what iteration yields. fn must be idempotent, since keys already
+// stored in the map may be normalized again.
+func WithKeyNormalizer[K comparable, V any](fn func(K) K) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.normalize = fn
+	}
+}
+
+// normalize applies the OrdMap's key normalizer to key, if it has one.
+func (om *OrdMap[K, V]) normalize(key K) K {
+	if om.opts.normalize == nil {
+		return key
+	}
+
+	return om.opts.normalize(key)
+}
@@ -0,0 +1,36 @@
+package ordmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_KeyNormalizer(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithKeyNormalizer[string, string](strings.ToLower))
+	om.Set("Content-Type", "text/html")
+	om.Set("CONTENT-TYPE", "application/json")
+	om.Set("Accept", "*/*")
+
+	if om.Len() != 2 {
+		t.Fatalf("expected keys differing only by case to collapse, got %d entries", om.Len())
+	}
+
+	if val, ok := om.Get("content-type"); !ok || val != "application/json" {
+		t.Fatalf("expected case-insensitive lookup to find the latest value, got %s", val)
+	}
+
+	if idx, ok := om.Index("ACCEPT"); !ok || idx != 1 {
+		t.Fatalf("expected ACCEPT to be at index 1, got %d", idx)
+	}
+
+	if entries := om.Entries(); entries[0].Key != "content-type" {
+		t.Fatalf("expected normalized keys to be stored, got %s", entries[0].Key)
+	}
+
+	om.Delete("Content-TYPE")
+	if om.Has("content-type") {
+		t.Fatal("expected delete to normalize its key")
+	}
+}
@@ -1,6 +1,12 @@
 package ordmap
 
-import "sync"
+import (
+	"context"
+	"iter"
+	"slices"
+	"sync/atomic"
+	"time"
+)
 
 // An Entry is a generic key/value pair within an OrdMap.
 type Entry[K comparable, V any] struct {
@@ -10,36 +16,192 @@ type Entry[K comparable, V any] struct {
 
 // An OrdMap is a generic, concurrency safe ordered map implementation. It works by storing entries in a slice to
 // preserve ordering while tracking key lookups to indices in order to fulfill typical O(1) map semantics. Storage
-// requirements should be roughly equivalent to map[K]V + map[K]int. Deletes are potentially slow because the
-// underlying slice has to be spliced.
+// requirements should be roughly equivalent to map[K]V + map[K]int. Deletes leave a tombstone in the underlying slice
+// which is cleaned up by a lazy compaction once tombstones make up more than half of it.
 type OrdMap[K comparable, V any] struct {
-	m sync.RWMutex
+	m    mutex
+	opts options[K, V]
 
-	lookup map[K]int
-	data   []Entry[K, V]
+	lookup     map[K]int
+	data       []Entry[K, V]
+	tombstones int
+	// length mirrors len(lookup) so Len and Has can read it without taking the lock.
+	length atomic.Int64
+	// filter is the key filter maintained when enabled with WithKeyFilter, published atomically so Has can read it
+	// without taking the lock.
+	filter atomic.Pointer[keyFilter[K]]
+	// front is the index of the first slot that may hold a live entry. Every slot before it is a tombstone.
+	front int
+	// peak is the largest number of keys the lookup map has had to hold since it was last rebuilt.
+	peak    int
+	version uint64
+	changes changeLog[K, V]
+	// evicted holds entries evicted while the write lock was held, so they can be handed to the eviction callback
+	// once it's released.
+	evicted []Entry[K, V]
+	// pending holds the sets and deletes made while the write lock was held, so they can be handed to the OnSet and
+	// OnDelete hooks once it's released.
+	pending []Change[K, V]
+
+	// expiries holds the deadlines of entries set with a TTL, and reaper controls the background goroutine that
+	// removes them once they pass.
+	expiries map[K]time.Time
+	reaper   *reaper
+
+	// shared is set while data and lookup are also referenced by a Snapshot, and must be copied before being modified.
+	shared bool
+
+	// journal records operations for Undo and Redo when enabled with WithJournal.
+	journal *journal[K, V]
+
+	// indexes holds the secondary indexes added with AddIndex, by name.
+	indexes map[string]valueIndex[K, V]
+
+	// watchers holds the channels subscribed with Watch and WatchAll.
+	watchers watchers[K, V]
+
+	// pinned holds the keys exempted from eviction, expiry, and truncation with Pin.
+	pinned map[K]struct{}
+
+	// metrics holds the counters reported by Metrics, and is nil unless enabled with WithMetrics.
+	metrics *metrics
+}
+
+const (
+	// minRebuildPeak is the smallest peak lookup size worth automatically rebuilding the lookup map for.
+	minRebuildPeak = 1024
+	// rebuildRatio is how many times smaller than its peak the lookup map has to get before it is automatically
+	// rebuilt.
+	rebuildRatio = 4
+)
+
+// An Option configures optional behavior of an OrdMap at construction time.
+type Option[K comparable, V any] func(*options[K, V])
+
+// options holds the configuration assembled from the Options passed to New.
+type options[K comparable, V any] struct {
+	changeLogSize int
+	intern        func(K) K
+	maxEntries    int
+	accessOrder   bool
+	moveOnUpdate  bool
+	onEvict       func(K, V)
+	onSet         func(K, V)
+	onDelete      func(K, V)
+	policy        EvictionPolicy[K]
+	keyIndex      keyIndex[K]
+	keyFilter     bool
+	normalize     func(K) K
+	journalSize   int
+	keyCodec      Codec[K]
+	valueCodec    Codec[V]
+	metrics       bool
+	unsafe        bool
+	defaultValue  func(K) V
 }
 
-// New returns a new OrdMap with allocations for data and lookup.
-func New[K comparable, V any](initialSize int) OrdMap[K, V] {
+// New returns a new, empty OrdMap with capacity preallocated for initialSize entries in both data and lookup.
+func New[K comparable, V any](initialSize int, opts ...Option[K, V]) OrdMap[K, V] {
+	var o options[K, V]
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var m *metrics
+	if o.metrics {
+		m = &metrics{}
+	}
+
 	return OrdMap[K, V]{
-		lookup: make(map[K]int),
-		data:   make([]Entry[K, V], initialSize),
+		m:       mutex{disabled: o.unsafe},
+		opts:    o,
+		lookup:  make(map[K]int, initialSize),
+		data:    make([]Entry[K, V], 0, initialSize),
+		peak:    initialSize,
+		changes: newChangeLog[K, V](o.changeLogSize),
+		journal: newJournal[K, V](o.journalSize),
+		metrics: m,
 	}
 }
 
-// Entries returns the ordered slice of Entry structs which can be iterated on.
+// Entries returns a newly allocated, ordered slice of the OrdMap's entries, copied under the read lock. The copy can be
+// kept and modified freely; use EntriesUnsafe to avoid the O(Len) copy when that isn't needed.
 func (om *OrdMap[K, V]) Entries() []Entry[K, V] {
+	return om.snapshot()
+}
+
+// EntriesUnsafe returns the OrdMap's internal slice of entries without copying it. Any pending tombstones are compacted
+// away first. The slice is only valid until the next write: writes may modify it in place or leave it stale, so it must
+// not be used concurrently with writers, and it must never be modified by the caller. It also still holds entries
+// whose TTL has expired but that haven't been reaped yet.
+func (om *OrdMap[K, V]) EntriesUnsafe() []Entry[K, V] {
 	om.m.RLock()
-	defer om.m.RUnlock()
+	if om.tombstones == 0 {
+		defer om.m.RUnlock()
+		return om.data
+	}
+	om.m.RUnlock()
+
+	om.m.Lock()
+	defer om.m.Unlock()
+	om.sweep()
 	return om.data
 }
 
-// Get implements a map lookup. This should semantically be O(1) and equivalent to val, ok := map[key].
+// All returns an iterator over the OrdMap's key/value pairs in order. It is equivalent to AllCtx with a context that is
+// never cancelled.
+func (om *OrdMap[K, V]) All() iter.Seq2[K, V] {
+	return om.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the OrdMap's key/value pairs in order. Iteration stops as soon as ctx is cancelled.
+// The read lock is held until iteration finishes or stops, so the loop body must not mutate the same OrdMap.
+func (om *OrdMap[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		om.m.RLock()
+		defer om.m.RUnlock()
+		now := time.Now()
+		for idx, entry := range om.data {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !om.visible(idx, now) {
+				continue
+			}
+
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Get implements a map lookup. This should semantically be O(1) and equivalent to val, ok := map[key]. When the OrdMap
+// was created with access ordering or an eviction policy, Get also records the access and has to take the write lock.
+// When it was created with WithDefault, Get inserts a default value for missing keys.
 func (om *OrdMap[K, V]) Get(key K) (V, bool) {
+	key = om.normalize(key)
+	val, ok := om.find(key)
+	// a key that only exists because a default was created for it is still a miss
+	om.metrics.get(ok)
+	if ok || om.opts.defaultValue == nil {
+		return val, ok
+	}
+
+	return om.getOrCreate(key), true
+}
+
+// find looks up a normalized key without creating a default value for it.
+func (om *OrdMap[K, V]) find(key K) (V, bool) {
+	if om.opts.accessOrder || om.opts.policy != nil {
+		return om.getAndTouch(key)
+	}
+
 	om.m.RLock()
 	defer om.m.RUnlock()
 	idx, ok := om.lookup[key]
-	if !ok {
+	if !ok || om.expired(key, time.Now()) {
 		var zero V
 		return zero, false
 	}
@@ -47,10 +209,21 @@ func (om *OrdMap[K, V]) Get(key K) (V, bool) {
 	return om.data[idx].Value, true
 }
 
-// Index returns the ordered index associated with the given key.
+// Index returns the ordered index associated with the given key. Any pending tombstones are compacted away first so
+// that the index matches the key's position in Entries.
 func (om *OrdMap[K, V]) Index(key K) (int, bool) {
+	key = om.normalize(key)
 	om.m.RLock()
-	defer om.m.RUnlock()
+	if om.tombstones == 0 {
+		defer om.m.RUnlock()
+		idx, ok := om.lookup[key]
+		return idx, ok
+	}
+	om.m.RUnlock()
+
+	om.m.Lock()
+	defer om.m.Unlock()
+	om.sweep()
 	idx, ok := om.lookup[key]
 	return idx, ok
 }
@@ -66,55 +239,327 @@ func (om *OrdMap[K, V]) Set(key K, val V) {
 // earlier values in the list will be overwritten.
 func (om *OrdMap[K, V]) BulkSet(entries ...Entry[K, V]) {
 	om.m.Lock()
-	defer om.m.Unlock()
+	defer om.unlock()
 	for _, entry := range entries {
-		idx, ok := om.lookup[entry.Key]
+		om.set(entry)
+	}
+}
+
+// set stores a single entry. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) set(entry Entry[K, V]) {
+	entry.Key = om.normalize(entry.Key)
+	om.unshare()
+	om.record(Change[K, V]{Op: OpSet, Key: entry.Key, Value: entry.Value})
+	if om.expiries != nil {
+		delete(om.expiries, entry.Key)
+	}
+
+	idx, ok := om.lookup[entry.Key]
+	if om.journal != nil {
+		var prev V
 		if ok {
-			om.data[idx] = entry
-			return
+			prev = om.data[idx].Value
+		}
+
+		om.journalSet(entry, prev, ok)
+	}
+
+	if ok {
+		// the stored key is kept so that an equal but separately allocated key doesn't replace it
+		om.removeFromIndexes(entry.Key, om.data[idx].Value)
+		om.data[idx].Value = entry.Value
+		om.addToIndexes(entry.Key, entry.Value)
+		if om.opts.policy != nil && !om.isPinned(entry.Key) {
+			om.opts.policy.OnSet(entry.Key)
 		}
 
-		om.lookup[entry.Key] = len(om.data)
-		om.data = append(om.data, entry)
+		if om.opts.accessOrder || om.opts.moveOnUpdate {
+			om.moveToBack(idx)
+		}
+		return
+	}
+
+	if om.opts.intern != nil {
+		entry.Key = om.opts.intern(entry.Key)
+	}
+
+	if len(om.data) == cap(om.data) {
+		om.metrics.grow()
+	}
+
+	om.lookup[entry.Key] = len(om.data)
+	om.data = append(om.data, entry)
+	om.length.Add(1)
+	om.filterAdd(entry.Key)
+	om.peak = max(om.peak, len(om.lookup))
+	if om.opts.keyIndex != nil {
+		om.opts.keyIndex.insert(entry.Key)
+	}
+
+	om.addToIndexes(entry.Key, entry.Value)
+
+	if om.opts.policy != nil {
+		om.opts.policy.OnSet(entry.Key)
+	}
+
+	om.evictOverflow()
+}
+
+// unlock releases the write lock and then runs any callbacks queued up while it was held, so callbacks are free to
+// use the OrdMap.
+func (om *OrdMap[K, V]) unlock() {
+	pending, evicted := om.pending, om.evicted
+	om.pending, om.evicted = nil, nil
+	om.m.Unlock()
+	for _, change := range pending {
+		if change.Op == OpSet {
+			om.opts.onSet(change.Key, change.Value)
+		} else {
+			om.opts.onDelete(change.Key, change.Value)
+		}
+	}
+
+	for _, entry := range evicted {
+		om.opts.onEvict(entry.Key, entry.Value)
 	}
 }
 
-// Has works the same as Get but does not return the value. It's included for convenience.
+// Grow preallocates space for at least n more entries, so that a following bulk load doesn't pay for repeated slice
+// growth and map rehashing. Go maps can't be grown in place, so the lookup map is rebuilt with the larger size, which
+// costs O(Len) up front.
+func (om *OrdMap[K, V]) Grow(n int) {
+	if n <= 0 {
+		return
+	}
+
+	om.m.Lock()
+	defer om.m.Unlock()
+	if cap(om.data)-len(om.data) < n {
+		om.metrics.grow()
+	}
+
+	om.data = slices.Grow(om.data, n)
+	lookup := make(map[K]int, len(om.lookup)+n)
+	for key, idx := range om.lookup {
+		lookup[key] = idx
+	}
+
+	om.lookup = lookup
+	om.peak = max(om.peak, len(om.lookup)+n)
+}
+
+// Compact releases memory held by the OrdMap beyond what its live entries need. Pending tombstones are swept, the data
+// slice is reallocated to exactly fit the live entries, and the lookup map is rebuilt since Go maps never shrink. This
+// is O(Len) and is intended for long-lived maps that have shrunk dramatically.
+func (om *OrdMap[K, V]) Compact() {
+	om.m.Lock()
+	defer om.m.Unlock()
+	om.sweep()
+	data := make([]Entry[K, V], len(om.data))
+	copy(data, om.data)
+	om.data = data
+	om.rebuildIndex()
+}
+
+// RebuildIndex reconstructs the key to index lookup map. Go maps never shrink, so a map that once held millions of
+// keys keeps its peak memory even after most of them are deleted. This happens automatically when a compaction finds
+// the lookup map has shrunk to a small fraction of its peak, but can be triggered explicitly after a known bulk
+// delete. Unlike Compact, the data slice is left alone.
+func (om *OrdMap[K, V]) RebuildIndex() {
+	om.m.Lock()
+	defer om.m.Unlock()
+	om.sweep()
+	om.rebuildIndex()
+}
+
+// rebuildIndex replaces the lookup map with a freshly allocated one sized for the live entries. Tombstones must
+// already have been swept and the write lock must be held by the caller.
+func (om *OrdMap[K, V]) rebuildIndex() {
+	lookup := make(map[K]int, len(om.data))
+	for idx, entry := range om.data {
+		lookup[entry.Key] = idx
+	}
+
+	om.lookup = lookup
+	om.peak = len(lookup)
+}
+
+// Has works the same as Get but does not return the value. It's included for convenience. Has on an empty OrdMap
+// returns without taking the lock, as does Has for a key ruled out by the filter enabled with WithKeyFilter. Every
+// other call takes the read lock.
 func (om *OrdMap[K, V]) Has(key K) bool {
+	if om.length.Load() == 0 {
+		return false
+	}
+
+	key = om.normalize(key)
+	if f := om.filter.Load(); f != nil && !f.mayContain(key) {
+		return false
+	}
+
 	om.m.RLock()
 	_, ok := om.lookup[key]
+	ok = ok && !om.expired(key, time.Now())
 	om.m.RUnlock()
 	return ok
 }
 
-// Delete a key from an OrdMap. This is not terribly performant, so be careful using this method in hot paths.
+// Delete a key from an OrdMap. Deletes are O(1): the entry's slot is marked as a tombstone and the underlying slice is
+// compacted once tombstones outnumber live entries, so the cost of compaction is amortized across deletes.
 func (om *OrdMap[K, V]) Delete(key K) {
 	om.m.Lock()
-	defer om.m.Unlock()
+	defer om.unlock()
+	om.delete(key)
+}
+
+// DeleteWithCompaction deletes key like Delete and returns how many tombstones were compacted as a result, which is
+// zero unless the delete triggered a compaction. Since it's measured under the write lock, the count isn't thrown off
+// by concurrent writes.
+func (om *OrdMap[K, V]) DeleteWithCompaction(key K) int {
+	om.m.Lock()
+	defer om.unlock()
+	before := om.tombstones
+	om.delete(key)
+	if om.tombstones >= before {
+		return 0
+	}
 
+	// the deleted slot itself became a tombstone before being compacted
+	return before + 1 - om.tombstones
+}
+
+// delete removes a single key. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) delete(key K) {
+	key = om.normalize(key)
 	idx, ok := om.lookup[key]
 	if !ok {
 		return
 	}
 
-	defer delete(om.lookup, key)
+	om.unshare()
+	if om.journal != nil {
+		om.journalDelete(idx)
+	}
+
+	om.record(Change[K, V]{Op: OpDelete, Key: key, Value: om.data[idx].Value})
+	om.forget(key)
 
-	if idx == 0 {
-		om.data = om.data[1:]
+	// the key is kept in the slot so live can tell it apart from a later re-insert of the same key, but the value is
+	// released right away
+	var zero V
+	om.data[idx].Value = zero
+	om.tombstones++
+	if om.tombstones*2 > len(om.data) {
+		om.sweep()
+	}
+}
+
+// SwapDelete removes a key in O(1) by moving the last entry into its slot. Unlike Delete this never leaves a tombstone
+// behind or triggers a compaction, but it does NOT preserve ordering: the last entry takes the deleted entry's
+// position. It's intended for callers that delete constantly in hot paths and only care about order occasionally.
+func (om *OrdMap[K, V]) SwapDelete(key K) {
+	key = om.normalize(key)
+	om.m.Lock()
+	defer om.unlock()
+	idx, ok := om.lookup[key]
+	if !ok {
 		return
 	}
 
-	if idx == len(om.data) {
-		om.data = om.data[0 : len(om.data)-1]
+	om.unshare()
+	if om.journal != nil {
+		om.journalDelete(idx)
+	}
+
+	om.record(Change[K, V]{Op: OpDelete, Key: key, Value: om.data[idx].Value})
+	om.forget(key)
+	om.tombstones++
+
+	// drop any trailing tombstones, including the deleted slot itself if it was last, so the final slot is live
+	for len(om.data) > 0 && !om.live(len(om.data)-1) {
+		om.data[len(om.data)-1] = Entry[K, V]{}
+		om.data = om.data[:len(om.data)-1]
+		om.tombstones--
+	}
+
+	if idx < len(om.data) {
+		last := len(om.data) - 1
+		om.data[idx] = om.data[last]
+		om.lookup[om.data[idx].Key] = idx
+		om.data[last] = Entry[K, V]{}
+		om.data = om.data[:last]
+		om.tombstones--
+	}
+
+	// popping tombstones can shrink data below front, and once none are left every slot is live
+	om.front = min(om.front, len(om.data))
+	if om.tombstones == 0 {
+		om.front = 0
+	}
+}
+
+// forget removes key from the lookup map and every auxiliary structure tracking it, leaving its slot in data for the
+// caller to deal with. The write lock must be held by the caller.
+func (om *OrdMap[K, V]) forget(key K) {
+	om.removeFromIndexes(key, om.data[om.lookup[key]].Value)
+	delete(om.lookup, key)
+	om.length.Add(-1)
+	delete(om.pinned, key)
+	if om.expiries != nil {
+		delete(om.expiries, key)
+	}
+
+	if om.opts.policy != nil {
+		om.opts.policy.OnDelete(key)
+	}
+
+	if om.opts.keyIndex != nil {
+		om.opts.keyIndex.remove(key)
+	}
+}
+
+// live reports whether the slot at idx holds a live entry rather than a tombstone. The read lock must be held by the
+// caller.
+func (om *OrdMap[K, V]) live(idx int) bool {
+	if om.tombstones == 0 {
+		return true
+	}
+
+	lookupIdx, ok := om.lookup[om.data[idx].Key]
+	return ok && lookupIdx == idx
+}
+
+// sweep removes tombstones from the underlying slice and rewrites the lookup indices of the entries that moved. The
+// write lock must be held by the caller.
+func (om *OrdMap[K, V]) sweep() {
+	if om.tombstones == 0 {
 		return
 	}
 
-	om.data = append(om.data[0:idx], om.data[idx+1:]...)
+	om.unshare()
+	live := 0
+	for idx, entry := range om.data {
+		if !om.live(idx) {
+			continue
+		}
+
+		om.data[live] = entry
+		om.lookup[entry.Key] = live
+		live++
+	}
+
+	clear(om.data[live:])
+	om.data = om.data[:live]
+	om.tombstones = 0
+	om.front = 0
+
+	if om.peak >= minRebuildPeak && len(om.lookup) < om.peak/rebuildRatio {
+		om.rebuildIndex()
+	}
 }
 
-// Len returns the current length of the OrdMap.
+// Len returns the current length of the OrdMap without taking the lock, so it's cheap to poll from a hot loop. Expired
+// entries are counted until they are reaped.
 func (om *OrdMap[K, V]) Len() int {
-	om.m.RLock()
-	defer om.m.RUnlock()
-	return len(om.data)
+	return int(om.length.Load())
 }
@@ -1,6 +1,7 @@
 package ordmap_test
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -90,3 +91,281 @@ func Test_ConcurrentAccess(t *testing.T) {
 		t.Fatalf("expected final map length to be 1000, got %d", om.Len())
 	}
 }
+
+func Test_AllCtx(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := 0; i < 100; i++ {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	idx := 0
+	for key, val := range om.AllCtx(context.Background()) {
+		if key != fmt.Sprintf("key %d", idx) || val != idx {
+			t.Fatalf("expected entry #%d to be %d, received key=%s val=%d", idx, idx, key, val)
+		}
+		idx++
+	}
+
+	if idx != 100 {
+		t.Fatalf("expected to iterate 100 entries, got %d", idx)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count := 0
+	for range om.AllCtx(ctx) {
+		count++
+		if count == 10 {
+			cancel()
+		}
+	}
+
+	if count != 10 {
+		t.Fatalf("expected iteration to stop after cancellation at 10 entries, got %d", count)
+	}
+
+	// the read lock must be released once iteration stops
+	om.Set("after", 1)
+}
+
+func Test_DeleteKeepsIndicesConsistent(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := 0; i < 10; i++ {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	om.Delete("key 0")
+	om.Delete("key 4")
+	om.Set("key 0", 0)
+
+	expected := []int{1, 2, 3, 5, 6, 7, 8, 9, 0}
+	if om.Len() != len(expected) {
+		t.Fatalf("expected map length to be %d, got %d", len(expected), om.Len())
+	}
+
+	for idx, val := range expected {
+		key := fmt.Sprintf("key %d", val)
+		got, ok := om.Get(key)
+		if !ok || got != val {
+			t.Fatalf("expected %s to be %d, got %d", key, val, got)
+		}
+
+		pos, ok := om.Index(key)
+		if !ok || pos != idx {
+			t.Fatalf("expected %s to be at index %d, got %d", key, idx, pos)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		om.Delete(fmt.Sprintf("key %d", i))
+	}
+
+	if om.Len() != 0 || len(om.Entries()) != 0 {
+		t.Fatalf("expected map to be empty after deleting every key, got length %d", om.Len())
+	}
+}
+
+func Test_InitialSizeIsCapacity(t *testing.T) {
+	om := ordmap.New[string, int](100)
+
+	if om.Len() != 0 || len(om.Entries()) != 0 {
+		t.Fatalf("expected a new map to be empty regardless of initial size, got length %d", om.Len())
+	}
+
+	om.Set("first", 1)
+	if idx, _ := om.Index("first"); idx != 0 {
+		t.Fatalf("expected first key to be at index 0, got %d", idx)
+	}
+}
+
+func Test_Grow(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.Set("first", 1)
+	om.Grow(1000)
+
+	if om.Cap() < 1001 {
+		t.Fatalf("expected capacity for at least 1001 entries, got %d", om.Cap())
+	}
+
+	if val, ok := om.Get("first"); !ok || val != 1 || om.Len() != 1 {
+		t.Fatal("expected existing entries to survive Grow")
+	}
+}
+
+func Test_Compact(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := 0; i < 1000; i++ {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	for i := 0; i < 990; i++ {
+		om.Delete(fmt.Sprintf("key %d", i))
+	}
+
+	om.Compact()
+	entries := om.Entries()
+	if len(entries) != 10 || cap(entries) != 10 {
+		t.Fatalf("expected compacted data to hold exactly 10 entries, got len=%d cap=%d", len(entries), cap(entries))
+	}
+
+	for idx, entry := range entries {
+		if pos, ok := om.Index(entry.Key); !ok || pos != idx {
+			t.Fatalf("expected %s to be at index %d, got %d", entry.Key, idx, pos)
+		}
+	}
+}
+
+func Test_RebuildIndex(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := 0; i < 5000; i++ {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	// deleting half of the keys leaves the lookup map at its peak size, so rebuild it explicitly
+	for i := 0; i < 4990; i += 2 {
+		om.Delete(fmt.Sprintf("key %d", i))
+	}
+
+	om.RebuildIndex()
+	for idx, entry := range om.Entries() {
+		if pos, ok := om.Index(entry.Key); !ok || pos != idx {
+			t.Fatalf("expected %s to be at index %d, got %d", entry.Key, idx, pos)
+		}
+
+		if val, ok := om.Get(entry.Key); !ok || val != entry.Value {
+			t.Fatalf("expected %s to be %d, got %d", entry.Key, entry.Value, val)
+		}
+	}
+
+	if om.Len() != 2505 {
+		t.Fatalf("expected map length to be 2505, got %d", om.Len())
+	}
+}
+
+func Test_SwapDelete(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := 0; i < 5; i++ {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	om.Delete("key 4")
+	om.SwapDelete("key 1")
+	om.SwapDelete("missing")
+
+	expected := []string{"key 0", "key 3", "key 2"}
+	if om.Len() != len(expected) {
+		t.Fatalf("expected map length to be %d, got %d", len(expected), om.Len())
+	}
+
+	for idx, entry := range om.Entries() {
+		if entry.Key != expected[idx] {
+			t.Fatalf("expected entry #%d to be %s, got %s", idx, expected[idx], entry.Key)
+		}
+
+		if pos, ok := om.Index(entry.Key); !ok || pos != idx {
+			t.Fatalf("expected %s to be at index %d, got %d", entry.Key, idx, pos)
+		}
+	}
+
+	om.SwapDelete("key 2")
+	om.SwapDelete("key 0")
+	om.SwapDelete("key 3")
+	if om.Len() != 0 || len(om.Entries()) != 0 {
+		t.Fatalf("expected map to be empty, got length %d", om.Len())
+	}
+}
+
+func Test_EntriesCopies(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.Set("a", 1)
+	om.Set("b", 2)
+
+	entries := om.Entries()
+	entries[0].Value = 100
+	if val, _ := om.Get("a"); val != 1 {
+		t.Fatalf("expected modifying Entries not to affect the map, got %d", val)
+	}
+
+	om.Set("b", 20)
+	if entries[1].Value != 2 {
+		t.Fatalf("expected Entries not to observe later writes, got %d", entries[1].Value)
+	}
+
+	if unsafe := om.EntriesUnsafe(); len(unsafe) != 2 || unsafe[1].Value != 20 {
+		t.Fatalf("expected EntriesUnsafe to return the live entries, got %v", unsafe)
+	}
+}
+
+func Test_LenAndHasWithoutLock(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	if om.Has("a") {
+		t.Fatal("expected an empty map to have no keys")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			om.Set(fmt.Sprintf("%d", i), i)
+			if i%2 == 0 {
+				om.Delete(fmt.Sprintf("%d", i))
+			}
+		}
+	}()
+
+	for polling := true; polling; {
+		select {
+		case <-done:
+			polling = false
+		default:
+			if n := om.Len(); n < 0 || n > 500 {
+				t.Fatalf("expected length to stay between 0 and 500, got %d", n)
+			}
+
+			om.Has("1")
+		}
+	}
+
+	if om.Len() != 500 || !om.Has("999") || om.Has("998") {
+		t.Fatalf("expected 500 odd keys to remain, got %d", om.Len())
+	}
+
+	if err := om.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_DeleteWithCompaction(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := range 4 {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	if n := om.DeleteWithCompaction("key 0"); n != 0 {
+		t.Fatalf("expected the first delete not to compact, got %d", n)
+	}
+
+	if n := om.DeleteWithCompaction("missing"); n != 0 {
+		t.Fatalf("expected deleting a missing key not to compact, got %d", n)
+	}
+
+	om.DeleteWithCompaction("key 1")
+	if n := om.DeleteWithCompaction("key 2"); n != 3 {
+		t.Fatalf("expected the delete pushing tombstones past half to compact 3, got %d", n)
+	}
+}
+
+func Test_BulkSetUpdatesAndInserts(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.Set("a", 1)
+	om.BulkSet(
+		ordmap.Entry[string, int]{Key: "a", Value: 10},
+		ordmap.Entry[string, int]{Key: "b", Value: 2},
+		ordmap.Entry[string, int]{Key: "c", Value: 3},
+	)
+
+	// an update early in the batch used to return before the rest of it was set
+	if got := fmt.Sprint(om.Entries()); got != "[{a 10} {b 2} {c 3}]" {
+		t.Fatalf("expected every entry in the batch to be set, got %s", got)
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/eriktate/go-ordmap/ordmapotel
+
+go 1.25.0
+
+require (
+	github.com/eriktate/go-ordmap v0.0.0-20261017015726-c8be1ffc8a59
+	go.opentelemetry.io/otel v1.46.0
+	go.opentelemetry.io/otel/trace v1.46.0
+)
+
+require github.com/cespare/xxhash/v2 v2.3.0 // indirect
@@ -0,0 +1,74 @@
+// Package ordmapotel wraps an OrdMap to record OpenTelemetry spans for its expensive operations, so that latency
+// caused by large batches, compaction, and full iterations shows up in traces. Cheap operations like Get and Set pass
+// straight through without a span. It's a separate module, so only programs importing it depend on OpenTelemetry.
+package ordmapotel
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/eriktate/go-ordmap"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// A Map is an OrdMap whose expensive operations take a context and record spans with a tracer. Every other OrdMap
+// method is promoted unchanged.
+type Map[K comparable, V any] struct {
+	*ordmap.OrdMap[K, V]
+	tracer trace.Tracer
+}
+
+// Wrap returns a Map recording spans for om with tracer.
+func Wrap[K comparable, V any](om *ordmap.OrdMap[K, V], tracer trace.Tracer) *Map[K, V] {
+	return &Map[K, V]{OrdMap: om, tracer: tracer}
+}
+
+// BulkSet sets entries within an "ordmap.BulkSet" span recording how many there were.
+func (m *Map[K, V]) BulkSet(ctx context.Context, entries ...ordmap.Entry[K, V]) {
+	_, span := m.tracer.Start(ctx, "ordmap.BulkSet", trace.WithAttributes(attribute.Int("ordmap.entries", len(entries))))
+	defer span.End()
+	m.OrdMap.BulkSet(entries...)
+}
+
+// Delete deletes key. Most deletes are O(1) and aren't traced, but a delete that triggers compaction of the
+// OrdMap's tombstones is recorded as an "ordmap.Delete" span after the fact, along with how many were compacted.
+func (m *Map[K, V]) Delete(ctx context.Context, key K) {
+	start := time.Now()
+	compacted := m.DeleteWithCompaction(key)
+	if compacted == 0 {
+		return
+	}
+
+	_, span := m.tracer.Start(ctx, "ordmap.Delete", trace.WithTimestamp(start),
+		trace.WithAttributes(attribute.Int("ordmap.tombstones", compacted)))
+	span.End()
+}
+
+// Compact compacts the OrdMap within an "ordmap.Compact" span recording how many entries it held.
+func (m *Map[K, V]) Compact(ctx context.Context) {
+	_, span := m.tracer.Start(ctx, "ordmap.Compact", trace.WithAttributes(attribute.Int("ordmap.entries", m.Len())))
+	defer span.End()
+	m.OrdMap.Compact()
+}
+
+// All returns an iterator over the OrdMap like AllCtx, recording an "ordmap.All" span from the start of iteration
+// until it finishes or stops, along with how many entries were visited.
+func (m *Map[K, V]) All(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ctx, span := m.tracer.Start(ctx, "ordmap.All")
+		visited := 0
+		defer func() {
+			span.SetAttributes(attribute.Int("ordmap.visited", visited))
+			span.End()
+		}()
+
+		for key, val := range m.AllCtx(ctx) {
+			visited++
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ordmapotel_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+	"github.com/eriktate/go-ordmap/ordmapotel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recorder is a no-op tracer that remembers the names of the spans it started
+type recorder struct {
+	trace.Tracer
+	spans []string
+}
+
+func (r *recorder) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	r.spans = append(r.spans, name)
+	return r.Tracer.Start(ctx, name, opts...)
+}
+
+func Test_Map(t *testing.T) {
+	ctx := context.Background()
+	tracer := &recorder{Tracer: noop.NewTracerProvider().Tracer("test")}
+	om := ordmap.New[string, int](0)
+	m := ordmapotel.Wrap(&om, tracer)
+
+	entries := make([]ordmap.Entry[string, int], 10)
+	for i := range entries {
+		entries[i] = ordmap.Entry[string, int]{Key: fmt.Sprintf("key %d", i), Value: i}
+	}
+
+	m.BulkSet(ctx, entries...)
+	m.Set("key 10", 10)
+	for i := range 6 {
+		m.Delete(ctx, fmt.Sprintf("key %d", i))
+	}
+
+	visited := 0
+	for range m.All(ctx) {
+		visited++
+	}
+
+	if visited != 5 {
+		t.Fatalf("expected to visit 5 entries, visited %d", visited)
+	}
+
+	// only the delete that pushed tombstones past half of the slots compacted them
+	expected := "[ordmap.BulkSet ordmap.Delete ordmap.All]"
+	if got := fmt.Sprint(tracer.spans); got != expected {
+		t.Fatalf("expected spans %s, got %s", expected, got)
+	}
+}
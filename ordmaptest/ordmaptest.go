@@ -0,0 +1,191 @@
+// Package ordmaptest provides helpers for testing code that uses ordered maps: an Equal assertion that reports an
+// ordered diff, builders for fixture maps, and a harness for stress testing a Map under concurrent use.
+package ordmaptest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+// Equal reports a test error unless want and got hold equal entries in the same order. Values are compared with
+// reflect.DeepEqual. The error shows a diff of the two orderings, with entries only in want prefixed by - and entries
+// only in got prefixed by +.
+func Equal[K comparable, V any](t testing.TB, want, got ordmap.ReadOnly[K, V]) bool {
+	t.Helper()
+	if diff := Diff(want, got); diff != "" {
+		t.Errorf("ordered maps differ (-want +got):\n%s", diff)
+		return false
+	}
+
+	return true
+}
+
+// Diff returns a line per entry describing how got differs from want, or an empty string if they hold equal entries
+// in the same order. Entries common to both are prefixed by two spaces, so the diff shows where differences sit in
+// the ordering.
+func Diff[K comparable, V any](want, got ordmap.ReadOnly[K, V]) string {
+	a, b := want.Entries(), got.Entries()
+	equal := func(x, y ordmap.Entry[K, V]) bool {
+		return x.Key == y.Key && reflect.DeepEqual(x.Value, y.Value)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if equal(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	if lcs[0][0] == len(a) && len(a) == len(b) {
+		return ""
+	}
+
+	var sb strings.Builder
+	line := func(prefix string, entry ordmap.Entry[K, V]) {
+		fmt.Fprintf(&sb, "%s%v: %v\n", prefix, entry.Key, entry.Value)
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && equal(a[i], b[j]):
+			line("  ", a[i])
+			i, j = i+1, j+1
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			line("- ", a[i])
+			i++
+		default:
+			line("+ ", b[j])
+			j++
+		}
+	}
+
+	return sb.String()
+}
+
+// Of returns a new OrdMap holding pairs, given as alternating keys and values in order. It panics if pairs has an odd
+// length or holds a key or value of the wrong type, which is acceptable in test fixtures.
+func Of[K comparable, V any](pairs ...any) *ordmap.OrdMap[K, V] {
+	if len(pairs)%2 != 0 {
+		panic("ordmaptest: Of needs an even number of arguments")
+	}
+
+	om := ordmap.New[K, V](len(pairs) / 2)
+	for idx := 0; idx < len(pairs); idx += 2 {
+		om.Set(pairs[idx].(K), pairs[idx+1].(V))
+	}
+
+	return &om
+}
+
+// Numbered returns a new OrdMap holding n entries, with keys "key 0" through "key n-1" mapped to their number.
+func Numbered(n int) *ordmap.OrdMap[string, int] {
+	om := ordmap.New[string, int](n)
+	for i := range n {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	return &om
+}
+
+// StressOptions configures Stress. Zero fields take the defaults noted on each.
+type StressOptions struct {
+	// Goroutines is how many goroutines operate on the map at once. Defaults to 8.
+	Goroutines int
+	// Ops is how many operations each goroutine performs. Defaults to 1000.
+	Ops int
+	// Keys is how many distinct keys are used. Fewer keys means more contention on each. Defaults to 64.
+	Keys int
+	// Seed seeds the random choice of operations, so a failing run can be reproduced.
+	Seed int64
+}
+
+// Stress hammers m with a random mix of Set, BulkSet, Get, Has, Index, Delete, and full iterations from several
+// goroutines at once, then checks that m is still consistent: iteration, Len, Has, and Index must agree with each
+// other, and m's own Validate method, if it has one, must pass. Run it with the race detector to get the most out of
+// it.
+func Stress(t testing.TB, m ordmap.Map[int, int], opts StressOptions) {
+	t.Helper()
+	opts.Goroutines = cmpOr(opts.Goroutines, 8)
+	opts.Ops = cmpOr(opts.Ops, 1000)
+	opts.Keys = cmpOr(opts.Keys, 64)
+
+	var wg sync.WaitGroup
+	for g := range opts.Goroutines {
+		wg.Add(1)
+		go func(rng *rand.Rand) {
+			defer wg.Done()
+			for op := range opts.Ops {
+				key := rng.Intn(opts.Keys)
+				switch rng.Intn(8) {
+				case 0, 1:
+					m.Set(key, op)
+				case 2:
+					m.BulkSet(
+						ordmap.Entry[int, int]{Key: key, Value: op},
+						ordmap.Entry[int, int]{Key: key + 1, Value: op},
+					)
+				case 3:
+					m.Get(key)
+				case 4:
+					m.Has(key)
+				case 5:
+					m.Index(key)
+				case 6:
+					m.Delete(key)
+				default:
+					for range m.All() {
+					}
+				}
+			}
+		}(rand.New(rand.NewSource(opts.Seed + int64(g))))
+	}
+
+	wg.Wait()
+	count := 0
+	for key := range m.All() {
+		if !m.Has(key) {
+			t.Errorf("key %d was iterated but Has reports it missing", key)
+		}
+
+		if idx, ok := m.Index(key); !ok || idx != count {
+			t.Errorf("key %d was iterated at position %d but Index reports %d, %t", key, count, idx, ok)
+		}
+
+		count++
+	}
+
+	if count != m.Len() {
+		t.Errorf("iterated %d entries but Len reports %d", count, m.Len())
+	}
+
+	if v, ok := m.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			t.Errorf("map is inconsistent after stress: %v", err)
+		}
+	}
+}
+
+// cmpOr returns val, or def if val isn't positive.
+func cmpOr(val, def int) int {
+	if val > 0 {
+		return val
+	}
+
+	return def
+}
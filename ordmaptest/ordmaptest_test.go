@@ -0,0 +1,51 @@
+package ordmaptest_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+	"github.com/eriktate/go-ordmap/ordmaptest"
+)
+
+func Test_Diff(t *testing.T) {
+	want := ordmaptest.Of[string, int]("a", 1, "b", 2, "c", 3)
+	if diff := ordmaptest.Diff[string, int](want, ordmaptest.Of[string, int]("a", 1, "b", 2, "c", 3)); diff != "" {
+		t.Fatalf("expected no diff between equal maps, got:\n%s", diff)
+	}
+
+	got := ordmaptest.Of[string, int]("a", 1, "c", 3, "b", 20, "d", 4)
+	expected := "  a: 1\n- b: 2\n  c: 3\n+ b: 20\n+ d: 4\n"
+	if diff := ordmaptest.Diff[string, int](want, got); diff != expected {
+		t.Fatalf("expected diff:\n%s\ngot:\n%s", expected, diff)
+	}
+
+	numbered := ordmaptest.Of[string, int]("key 0", 0, "key 1", 1, "key 2", 2)
+	if !ordmaptest.Equal[string, int](t, ordmaptest.Numbered(3), numbered) {
+		t.Fatal("expected Numbered to build key 0 through key 2")
+	}
+}
+
+func Test_Stress(t *testing.T) {
+	om := ordmap.New[int, int](0)
+	linked := ordmap.NewLinked[int, int]()
+	sharded := ordmap.NewSharded[int, int](4)
+	cow := ordmap.NewCopyOnWrite[int, int]()
+	readMostly := ordmap.NewReadMostly[int, int]()
+	sorted := ordmap.NewSortedOrdered[int, int]()
+	priority := ordmap.NewPriority[int, int]()
+	maps := map[string]ordmap.Map[int, int]{
+		"OrdMap":      &om,
+		"Linked":      &linked,
+		"Sharded":     &sharded,
+		"CopyOnWrite": &cow,
+		"ReadMostly":  &readMostly,
+		"SortedMap":   &sorted,
+		"Priority":    &priority,
+	}
+
+	for name, m := range maps {
+		t.Run(name, func(t *testing.T) {
+			ordmaptest.Stress(t, m, ordmaptest.StressOptions{Ops: 500, Seed: 1})
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package ordmap
+
+import (
+	"sync"
+	"time"
+)
+
+// ForEachParallel calls fn for every entry in the OrdMap using a pool of at most workers goroutines and returns once
+// every call has completed. Entries are snapshotted under the read lock before being dispatched, so fn is free to
+// mutate the OrdMap. The order in which fn is called is not defined; use CollectParallel when results need to line up
+// with the map's ordering. A workers value less than 1 is treated as 1.
+func (om *OrdMap[K, V]) ForEachParallel(workers int, fn func(K, V)) {
+	entries := om.snapshot()
+	parallel(len(entries), workers, func(idx int) {
+		fn(entries[idx].Key, entries[idx].Value)
+	})
+}
+
+// CollectParallel works like ForEachParallel but gathers the value returned by each call to fn. The returned slice is
+// ordered to match the OrdMap's entries at the time of the call, regardless of which worker finished first.
+func CollectParallel[K comparable, V, R any](om *OrdMap[K, V], workers int, fn func(K, V) R) []R {
+	entries := om.snapshot()
+	results := make([]R, len(entries))
+	parallel(len(entries), workers, func(idx int) {
+		results[idx] = fn(entries[idx].Key, entries[idx].Value)
+	})
+
+	return results
+}
+
+// snapshot returns a copy of the current entries taken under the read lock.
+func (om *OrdMap[K, V]) snapshot() []Entry[K, V] {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	return om.visibleEntries(time.Now())
+}
+
+// visibleEntries returns a copy of the entries that are live and unexpired as of now. The read lock must be held by the
+// caller.
+func (om *OrdMap[K, V]) visibleEntries(now time.Time) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(om.data)-om.tombstones)
+	for idx, entry := range om.data {
+		if om.visible(idx, now) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// parallel calls fn for every index in [0, n) across a bounded pool of goroutines.
+func parallel(n, workers int, fn func(idx int)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > n {
+		workers = n
+	}
+
+	indices := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			for idx := range indices {
+				fn(idx)
+			}
+			wg.Done()
+		}()
+	}
+
+	for idx := 0; idx < n; idx++ {
+		indices <- idx
+	}
+
+	close(indices)
+	wg.Wait()
+}
@@ -0,0 +1,35 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_ForEachParallel(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for i := 0; i < 1000; i++ {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	var sum atomic.Int64
+	om.ForEachParallel(8, func(_ string, val int) {
+		sum.Add(int64(val))
+	})
+
+	if sum.Load() != 499500 {
+		t.Fatalf("expected sum of all values to be 499500, got %d", sum.Load())
+	}
+
+	results := ordmap.CollectParallel(&om, 8, func(key string, val int) string {
+		return fmt.Sprintf("%s=%d", key, val)
+	})
+
+	for idx, res := range results {
+		if res != fmt.Sprintf("key %d=%d", idx, idx) {
+			t.Fatalf("expected result #%d to match entry order, got %s", idx, res)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ordmap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotMap is returned by SetPath when a path runs through a value that isn't a nested *OrdMap[string, any].
+var ErrNotMap = errors.New("ordmap: path runs through a value that isn't a map")
+
+// GetPath returns the value found by following path through nested maps, where every value along the way except the
+// last must be an *OrdMap[string, any]. It returns false if any key is missing or a value along the way isn't a map.
+// An empty path returns om itself. This is meant for editing ordered documents, such as JSON or YAML, in place.
+func GetPath(om *OrdMap[string, any], path ...string) (any, bool) {
+	if len(path) == 0 {
+		return om, true
+	}
+
+	parent, ok := parentOf(om, path)
+	if !ok {
+		return nil, false
+	}
+
+	return parent.Get(path[len(path)-1])
+}
+
+// SetPath sets the value at the end of path, creating empty nested maps for any missing keys along the way. New keys
+// are appended to their map like any other Set. It returns an error wrapping ErrNotMap if a value along the way exists
+// but isn't an *OrdMap[string, any], and sets nothing in that case, since maps are only created past the last
+// existing key.
+func SetPath(om *OrdMap[string, any], val any, path ...string) error {
+	if len(path) == 0 {
+		return errors.New("ordmap: SetPath needs at least one key")
+	}
+
+	for depth, key := range path[:len(path)-1] {
+		child, err := childMap(om, key)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, strings.Join(path[:depth+1], "."))
+		}
+
+		om = child
+	}
+
+	om.Set(path[len(path)-1], val)
+	return nil
+}
+
+// DeletePath deletes the key at the end of path from its nested map. Nothing happens if the path doesn't exist.
+// Maps left empty along the way are kept.
+func DeletePath(om *OrdMap[string, any], path ...string) {
+	if len(path) == 0 {
+		return
+	}
+
+	if parent, ok := parentOf(om, path); ok {
+		parent.Delete(path[len(path)-1])
+	}
+}
+
+// parentOf follows every key of path but the last, returning the map that should hold the last key.
+func parentOf(om *OrdMap[string, any], path []string) (*OrdMap[string, any], bool) {
+	for _, key := range path[:len(path)-1] {
+		val, ok := om.Get(key)
+		if !ok {
+			return nil, false
+		}
+
+		if om, ok = val.(*OrdMap[string, any]); !ok {
+			return nil, false
+		}
+	}
+
+	return om, true
+}
+
+// childMap returns the nested map stored at key, creating it if key is missing. Checking and creating happen under a
+// single write lock so concurrent SetPaths agree on the same child.
+func childMap(om *OrdMap[string, any], key string) (*OrdMap[string, any], error) {
+	key = om.normalize(key)
+	om.m.Lock()
+	defer om.unlock()
+	if idx, ok := om.lookup[key]; ok && !om.expired(key, time.Now()) {
+		child, ok := om.data[idx].Value.(*OrdMap[string, any])
+		if !ok {
+			return nil, ErrNotMap
+		}
+
+		return child, nil
+	}
+
+	child := New[string, any](0)
+	om.set(Entry[string, any]{Key: key, Value: &child})
+	return &child, nil
+}
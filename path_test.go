@@ -0,0 +1,47 @@
+package ordmap_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Path(t *testing.T) {
+	doc := ordmap.New[string, any](0)
+	doc.Set("name", "app")
+	for _, err := range []error{
+		ordmap.SetPath(&doc, 8080, "server", "port"),
+		ordmap.SetPath(&doc, "localhost", "server", "host"),
+		ordmap.SetPath(&doc, true, "server", "tls", "enabled"),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error setting path: %v", err)
+		}
+	}
+
+	if port, ok := ordmap.GetPath(&doc, "server", "port"); !ok || port != 8080 {
+		t.Fatalf("expected port 8080, got %v", port)
+	}
+
+	if _, ok := ordmap.GetPath(&doc, "name", "first"); ok {
+		t.Fatal("expected a path through a non-map value not to be found")
+	}
+
+	if err := ordmap.SetPath(&doc, "x", "name", "first"); !errors.Is(err, ordmap.ErrNotMap) {
+		t.Fatalf("expected setting through a non-map value to fail with ErrNotMap, got %v", err)
+	}
+
+	ordmap.DeletePath(&doc, "server", "host")
+	ordmap.DeletePath(&doc, "missing", "key")
+
+	data, err := json.Marshal(&doc)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling: %v", err)
+	}
+
+	if string(data) != `{"name":"app","server":{"port":8080,"tls":{"enabled":true}}}` {
+		t.Fatalf("unexpected document %s", data)
+	}
+}
@@ -0,0 +1,103 @@
+package ordmap
+
+import "time"
+
+// Pin exempts key from eviction by a size bound or eviction policy, from expiring with its TTL, and from Truncate and
+// Clear(true). It returns false if key is not present. A pinned entry keeps its position and can still be deleted or
+// overwritten explicitly; deleting it also unpins it. Once every entry of a bounded OrdMap is pinned, new keys are
+// evicted as soon as they are inserted.
+func (om *OrdMap[K, V]) Pin(key K) bool {
+	key = om.normalize(key)
+	om.m.Lock()
+	defer om.unlock()
+	if _, ok := om.lookup[key]; !ok || om.expired(key, time.Now()) {
+		return false
+	}
+
+	if om.pinned == nil {
+		om.pinned = make(map[K]struct{})
+	}
+
+	om.pinned[key] = struct{}{}
+	if om.opts.policy != nil {
+		om.opts.policy.OnDelete(key)
+	}
+
+	return true
+}
+
+// Unpin makes key subject to eviction and expiry again.
+func (om *OrdMap[K, V]) Unpin(key K) {
+	key = om.normalize(key)
+	om.m.Lock()
+	defer om.unlock()
+	if !om.isPinned(key) {
+		return
+	}
+
+	delete(om.pinned, key)
+	if om.opts.policy != nil {
+		om.opts.policy.OnSet(key)
+	}
+
+	om.evictOverflow()
+}
+
+// Pinned reports whether key is currently pinned.
+func (om *OrdMap[K, V]) Pinned(key K) bool {
+	key = om.normalize(key)
+	om.m.RLock()
+	defer om.m.RUnlock()
+	return om.isPinned(key)
+}
+
+// Truncate deletes every entry after the first n in the ordering, except pinned ones.
+func (om *OrdMap[K, V]) Truncate(n int) {
+	om.m.Lock()
+	defer om.unlock()
+	var keys []K
+	for idx := om.front; idx < len(om.data); idx++ {
+		if !om.live(idx) {
+			continue
+		}
+
+		if n > 0 {
+			n--
+			continue
+		}
+
+		if !om.isPinned(om.data[idx].Key) {
+			keys = append(keys, om.data[idx].Key)
+		}
+	}
+
+	for _, key := range keys {
+		om.delete(key)
+	}
+}
+
+// Clear deletes every entry from the OrdMap. If keepPinned is set, pinned entries are kept in their current order.
+func (om *OrdMap[K, V]) Clear(keepPinned bool) {
+	om.m.Lock()
+	defer om.unlock()
+	keys := make([]K, 0, len(om.lookup))
+	for idx := om.front; idx < len(om.data); idx++ {
+		if om.live(idx) && (!keepPinned || !om.isPinned(om.data[idx].Key)) {
+			keys = append(keys, om.data[idx].Key)
+		}
+	}
+
+	for _, key := range keys {
+		om.delete(key)
+	}
+}
+
+// isPinned reports whether key is pinned. The read lock must be held by the caller.
+func (om *OrdMap[K, V]) isPinned(key K) bool {
+	if len(om.pinned) == 0 {
+		return false
+	}
+
+	_, ok := om.pinned[key]
+	return ok
+}
@@ -0,0 +1,102 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_PinLRU(t *testing.T) {
+	var evicted []string
+	om := ordmap.NewLRU(2, ordmap.WithOnEvict(func(key string, _ int) {
+		evicted = append(evicted, key)
+	}))
+
+	om.Set("a", 1)
+	if om.Pin("missing") {
+		t.Fatal("expected pinning a missing key to fail")
+	}
+
+	if !om.Pin("a") || !om.Pinned("a") {
+		t.Fatal("expected a to be pinned")
+	}
+
+	om.Set("b", 2)
+	om.Set("c", 3)
+	om.Set("d", 4)
+	if got := fmt.Sprint(keys[string, int](&om)); got != "[a d]" {
+		t.Fatalf("expected the pinned entry to survive eviction, got %s", got)
+	}
+
+	om.Pin("d")
+	om.Set("e", 5)
+	if om.Has("e") || om.Len() != 2 {
+		t.Fatal("expected a new key to be evicted right away once every other entry is pinned")
+	}
+
+	om.Unpin("a")
+	om.Set("f", 6)
+	if got := fmt.Sprint(keys[string, int](&om)); got != "[d f]" {
+		t.Fatalf("expected the unpinned entry to be evicted again, got %s", got)
+	}
+
+	if fmt.Sprint(evicted) != "[b c e a]" {
+		t.Fatalf("unexpected evictions %v", evicted)
+	}
+}
+
+func Test_PinPolicy(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithEvictionPolicy[string, int](2, ordmap.NewLFUPolicy[string]()))
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Get("b")
+	om.Pin("a")
+	om.Set("c", 3)
+	if got := fmt.Sprint(keys[string, int](&om)); got != "[a b]" {
+		t.Fatalf("expected the pinned entry to be skipped by the policy, got %s", got)
+	}
+}
+
+func Test_PinTTL(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.SetWithTTL("a", 1, time.Millisecond)
+	om.SetWithTTL("b", 2, time.Millisecond)
+	om.Pin("a")
+	time.Sleep(5 * time.Millisecond)
+	om.Reap()
+	if !om.Has("a") || om.Has("b") {
+		t.Fatal("expected only the pinned entry to outlive its TTL")
+	}
+
+	om.Delete("a")
+	om.Set("a", 1)
+	if om.Pinned("a") {
+		t.Fatal("expected deleting a pinned key to unpin it")
+	}
+}
+
+func Test_TruncateAndClear(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	for idx, key := range []string{"a", "b", "c", "d", "e"} {
+		om.Set(key, idx)
+	}
+
+	om.Delete("b")
+	om.Pin("d")
+	om.Truncate(2)
+	if got := fmt.Sprint(keys[string, int](&om)); got != "[a c d]" {
+		t.Fatalf("expected truncate to keep the first 2 entries and pinned ones, got %s", got)
+	}
+
+	om.Clear(true)
+	if got := fmt.Sprint(keys[string, int](&om)); got != "[d]" {
+		t.Fatalf("expected clear to keep pinned entries, got %s", got)
+	}
+
+	om.Clear(false)
+	if om.Len() != 0 || om.Pinned("d") {
+		t.Fatal("expected clear to remove everything")
+	}
+}
@@ -0,0 +1,163 @@
+package ordmap
+
+// An EvictionPolicy decides which key a capacity bounded OrdMap evicts when it grows beyond its maximum size. Policies
+// are stateful and belong to a single OrdMap. Their methods are always called with the OrdMap's write lock held, so
+// implementations don't need any synchronization of their own.
+type EvictionPolicy[K comparable] interface {
+	// OnGet is called when key is successfully looked up with Get.
+	OnGet(key K)
+	// OnSet is called when key is inserted or has its value updated.
+	OnSet(key K)
+	// OnDelete is called when key is removed for any reason, including eviction, so the policy can forget it.
+	OnDelete(key K)
+	// Victim returns the key that should be evicted next, or false if the policy isn't tracking any keys.
+	Victim() (K, bool)
+}
+
+// WithEvictionPolicy bounds an OrdMap to maxEntries, evicting the key chosen by policy whenever inserting a new key
+// would exceed the bound. Unlike NewLRU, the policy never changes the map's insertion ordering. Since Get has to
+// report accesses to the policy, it takes the write lock rather than the read lock.
+func WithEvictionPolicy[K comparable, V any](maxEntries int, policy EvictionPolicy[K]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.maxEntries = maxEntries
+		o.policy = policy
+	}
+}
+
+// A queuePolicy tracks keys in a linked list and evicts from its front. With touch set, accesses move keys to the back
+// of the list, which turns first-in-first-out into least-recently-used.
+type queuePolicy[K comparable] struct {
+	queue Linked[K, struct{}]
+	touch bool
+}
+
+// NewFIFOPolicy returns an EvictionPolicy that evicts the key that was inserted first, regardless of how it has been
+// used since.
+func NewFIFOPolicy[K comparable]() EvictionPolicy[K] {
+	return &queuePolicy[K]{queue: NewLinked[K, struct{}]()}
+}
+
+// NewLRUPolicy returns an EvictionPolicy that evicts the key that was least recently read or written.
+func NewLRUPolicy[K comparable]() EvictionPolicy[K] {
+	return &queuePolicy[K]{queue: NewLinked[K, struct{}](), touch: true}
+}
+
+func (p *queuePolicy[K]) OnGet(key K) {
+	if p.touch {
+		p.queue.MoveToBack(key)
+	}
+}
+
+func (p *queuePolicy[K]) OnSet(key K) {
+	if p.queue.Has(key) {
+		p.OnGet(key)
+		return
+	}
+
+	p.queue.Set(key, struct{}{})
+}
+
+func (p *queuePolicy[K]) OnDelete(key K) {
+	p.queue.Delete(key)
+}
+
+func (p *queuePolicy[K]) Victim() (K, bool) {
+	if p.queue.head == nil {
+		var zero K
+		return zero, false
+	}
+
+	return p.queue.head.entry.Key, true
+}
+
+// An lfuPolicy groups keys into buckets by access count. Each bucket is ordered by when keys entered it, so ties are
+// broken by evicting the key that has sat at the lowest count the longest. Every operation is O(1) except deletes that
+// empty the lowest bucket, which have to find the next lowest count.
+type lfuPolicy[K comparable] struct {
+	counts  map[K]int
+	buckets map[int]*Linked[K, struct{}]
+	min     int
+}
+
+// NewLFUPolicy returns an EvictionPolicy that evicts the key that has been read or written the fewest times.
+func NewLFUPolicy[K comparable]() EvictionPolicy[K] {
+	return &lfuPolicy[K]{
+		counts:  make(map[K]int),
+		buckets: make(map[int]*Linked[K, struct{}]),
+	}
+}
+
+func (p *lfuPolicy[K]) OnGet(key K) {
+	count, ok := p.counts[key]
+	if !ok {
+		return
+	}
+
+	p.remove(key, count)
+	p.add(key, count+1)
+	if p.min == count && p.buckets[count] == nil {
+		p.min = count + 1
+	}
+}
+
+func (p *lfuPolicy[K]) OnSet(key K) {
+	if _, ok := p.counts[key]; ok {
+		p.OnGet(key)
+		return
+	}
+
+	p.add(key, 1)
+	p.min = 1
+}
+
+func (p *lfuPolicy[K]) OnDelete(key K) {
+	count, ok := p.counts[key]
+	if !ok {
+		return
+	}
+
+	p.remove(key, count)
+	delete(p.counts, key)
+	if p.min != count || p.buckets[count] != nil {
+		return
+	}
+
+	p.min = 0
+	for c := range p.buckets {
+		if p.min == 0 || c < p.min {
+			p.min = c
+		}
+	}
+}
+
+func (p *lfuPolicy[K]) Victim() (K, bool) {
+	bucket, ok := p.buckets[p.min]
+	if !ok {
+		var zero K
+		return zero, false
+	}
+
+	return bucket.head.entry.Key, true
+}
+
+// add puts key into the bucket for count.
+func (p *lfuPolicy[K]) add(key K, count int) {
+	bucket, ok := p.buckets[count]
+	if !ok {
+		b := NewLinked[K, struct{}]()
+		bucket = &b
+		p.buckets[count] = bucket
+	}
+
+	bucket.Set(key, struct{}{})
+	p.counts[key] = count
+}
+
+// remove takes key out of the bucket for count, dropping the bucket if it ends up empty.
+func (p *lfuPolicy[K]) remove(key K, count int) {
+	bucket := p.buckets[count]
+	bucket.Delete(key)
+	if bucket.head == nil {
+		delete(p.buckets, count)
+	}
+}
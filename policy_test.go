@@ -0,0 +1,47 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_EvictionPolicies(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   ordmap.EvictionPolicy[string]
+		expected []string
+	}{
+		{name: "fifo", policy: ordmap.NewFIFOPolicy[string](), expected: []string{"b", "c", "d"}},
+		{name: "lru", policy: ordmap.NewLRUPolicy[string](), expected: []string{"a", "c", "d"}},
+		{name: "lfu", policy: ordmap.NewLFUPolicy[string](), expected: []string{"a", "b", "d"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			om := ordmap.New(0, ordmap.WithEvictionPolicy[string, int](3, c.policy))
+			om.Set("a", 1)
+			om.Set("b", 2)
+			om.Set("b", 2)
+			om.Set("c", 3)
+			om.Get("a")
+			om.Get("c")
+			om.Get("b")
+			om.Get("a")
+			om.Delete("c")
+			om.Set("c", 3)
+			om.Set("d", 4)
+
+			keys := make([]string, 0, om.Len())
+			for key := range om.All() {
+				keys = append(keys, key)
+			}
+
+			// eviction never changes the insertion ordering of the survivors
+			if fmt.Sprint(keys) != fmt.Sprint(c.expected) {
+				t.Fatalf("expected %v to survive, got %v", c.expected, keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package ordmap
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A keyIndex is an auxiliary structure that an OrdMap keeps in sync with its set of keys.
+type keyIndex[K comparable] interface {
+	insert(key K)
+	remove(key K)
+}
+
+// A prefixIndex keeps an OrdMap's string keys sorted, so that every key sharing a prefix sits in one contiguous run
+// that can be found with binary search.
+type prefixIndex struct {
+	keys []string
+}
+
+func (pi *prefixIndex) insert(key string) {
+	idx, found := slices.BinarySearch(pi.keys, key)
+	if !found {
+		pi.keys = slices.Insert(pi.keys, idx, key)
+	}
+}
+
+func (pi *prefixIndex) remove(key string) {
+	idx, found := slices.BinarySearch(pi.keys, key)
+	if found {
+		pi.keys = slices.Delete(pi.keys, idx, idx+1)
+	}
+}
+
+// WithPrefixIndex maintains a sorted index of an OrdMap's string keys so that ScanPrefix only visits matching keys
+// instead of scanning the whole map. The index costs an extra string header per key, and makes inserting new keys and
+// deleting keys O(n) in the worst case because the sorted index has to be shifted.
+func WithPrefixIndex[V any]() Option[string, V] {
+	return func(o *options[string, V]) {
+		o.keyIndex = &prefixIndex{}
+	}
+}
+
+// ScanPrefix returns an iterator over the entries of om whose keys start with prefix, in om's order. Maps created with
+// WithPrefixIndex find the matching keys with a binary search; other maps fall back to an O(n) scan. Maps with a key
+// normalizer apply it to prefix as well. The read lock is held until iteration finishes or stops, so the loop body
+// must not mutate om.
+func ScanPrefix[V any](om *OrdMap[string, V], prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		prefix := om.normalize(prefix)
+		om.m.RLock()
+		defer om.m.RUnlock()
+		now := time.Now()
+		index, ok := om.opts.keyIndex.(*prefixIndex)
+		if !ok {
+			for idx, entry := range om.data {
+				if !strings.HasPrefix(entry.Key, prefix) || !om.visible(idx, now) {
+					continue
+				}
+
+				if !yield(entry.Key, entry.Value) {
+					return
+				}
+			}
+			return
+		}
+
+		start, _ := slices.BinarySearch(index.keys, prefix)
+		var matches []int
+		for _, key := range index.keys[start:] {
+			if !strings.HasPrefix(key, prefix) {
+				break
+			}
+
+			matches = append(matches, om.lookup[key])
+		}
+
+		slices.Sort(matches)
+		for _, idx := range matches {
+			if !om.visible(idx, now) {
+				continue
+			}
+
+			if !yield(om.data[idx].Key, om.data[idx].Value) {
+				return
+			}
+		}
+	}
+}
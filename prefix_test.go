@@ -0,0 +1,31 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_ScanPrefix(t *testing.T) {
+	indexed := ordmap.New(0, ordmap.WithPrefixIndex[int]())
+	plain := ordmap.New[string, int](0)
+	for idx, key := range []string{"db.port", "app.name", "db.host", "dbx", "app.env", "db.user"} {
+		indexed.Set(key, idx)
+		plain.Set(key, idx)
+	}
+
+	indexed.Delete("db.host")
+	plain.Delete("db.host")
+
+	for name, om := range map[string]*ordmap.OrdMap[string, int]{"indexed": &indexed, "plain": &plain} {
+		var keys []string
+		for key := range ordmap.ScanPrefix(om, "db.") {
+			keys = append(keys, key)
+		}
+
+		if fmt.Sprint(keys) != "[db.port db.user]" {
+			t.Fatalf("%s: expected db. keys in map order, got %v", name, keys)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package ordmap
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// A PriorityOrdMap is a concurrency safe map ordered by a priority attached to each entry, lowest first. Entries with
+// equal priorities stay in the order they were given that priority, so the map suits task registries and plugin
+// chains that have to run in a well defined order. Entries are kept in a slice sorted by priority, so Get and Index
+// are O(log n) binary searches, and inserts, deletes, and priority changes are O(n) since they shift the slice.
+type PriorityOrdMap[K comparable, V any] struct {
+	m sync.RWMutex
+
+	data []prioritized[K, V]
+	// rank locates every key's slot in data by its position in the ordering
+	rank map[K]priorityRank
+	seq  uint64
+}
+
+// A priorityRank orders entries by priority, breaking ties by the sequence number assigned when the entry was given
+// its priority.
+type priorityRank struct {
+	priority int
+	seq      uint64
+}
+
+func (a priorityRank) compare(b priorityRank) int {
+	if c := cmp.Compare(a.priority, b.priority); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.seq, b.seq)
+}
+
+// A prioritized is an entry stored in a PriorityOrdMap along with its rank.
+type prioritized[K comparable, V any] struct {
+	entry Entry[K, V]
+	rank  priorityRank
+}
+
+// NewPriority returns a new, empty PriorityOrdMap.
+func NewPriority[K comparable, V any]() PriorityOrdMap[K, V] {
+	return PriorityOrdMap[K, V]{rank: make(map[K]priorityRank)}
+}
+
+// search returns the position of key within data and whether it is present. The read lock must be held by the caller.
+func (pm *PriorityOrdMap[K, V]) search(key K) (int, bool) {
+	rank, ok := pm.rank[key]
+	if !ok {
+		return 0, false
+	}
+
+	return slices.BinarySearchFunc(pm.data, rank, func(p prioritized[K, V], rank priorityRank) int {
+		return p.rank.compare(rank)
+	})
+}
+
+// Entries returns a newly allocated slice of the map's entries in priority order.
+func (pm *PriorityOrdMap[K, V]) Entries() []Entry[K, V] {
+	pm.m.RLock()
+	defer pm.m.RUnlock()
+	entries := make([]Entry[K, V], len(pm.data))
+	for idx, p := range pm.data {
+		entries[idx] = p.entry
+	}
+
+	return entries
+}
+
+// All returns an iterator over the map's key/value pairs in priority order. It is equivalent to AllCtx with a context
+// that is never cancelled.
+func (pm *PriorityOrdMap[K, V]) All() iter.Seq2[K, V] {
+	return pm.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the map's key/value pairs in priority order. Iteration stops as soon as ctx is
+// cancelled. The read lock is held until iteration finishes or stops, so the loop body must not mutate the same map.
+func (pm *PriorityOrdMap[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		pm.m.RLock()
+		defer pm.m.RUnlock()
+		for _, p := range pm.data {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !yield(p.entry.Key, p.entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Get implements an O(log n) map lookup.
+func (pm *PriorityOrdMap[K, V]) Get(key K) (V, bool) {
+	pm.m.RLock()
+	defer pm.m.RUnlock()
+	idx, ok := pm.search(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return pm.data[idx].entry.Value, true
+}
+
+// Priority returns the priority of key.
+func (pm *PriorityOrdMap[K, V]) Priority(key K) (int, bool) {
+	pm.m.RLock()
+	defer pm.m.RUnlock()
+	rank, ok := pm.rank[key]
+	return rank.priority, ok
+}
+
+// Index returns the position of key in priority order.
+func (pm *PriorityOrdMap[K, V]) Index(key K) (int, bool) {
+	pm.m.RLock()
+	defer pm.m.RUnlock()
+	idx, ok := pm.search(key)
+	if !ok {
+		return 0, false
+	}
+
+	return idx, true
+}
+
+// Has works the same as Get but does not return the value. It's included for convenience.
+func (pm *PriorityOrdMap[K, V]) Has(key K) bool {
+	pm.m.RLock()
+	defer pm.m.RUnlock()
+	_, ok := pm.rank[key]
+	return ok
+}
+
+// Len returns the current length of the map.
+func (pm *PriorityOrdMap[K, V]) Len() int {
+	pm.m.RLock()
+	defer pm.m.RUnlock()
+	return len(pm.data)
+}
+
+// Set a key/value pair within the map. Existing keys keep their priority and position, and new keys get priority
+// zero.
+func (pm *PriorityOrdMap[K, V]) Set(key K, val V) {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if idx, ok := pm.search(key); ok {
+		pm.data[idx].entry.Value = val
+		return
+	}
+
+	pm.insert(Entry[K, V]{Key: key, Value: val}, 0)
+}
+
+// BulkSet sets many entries at once under a single lock, in the same way as Set.
+func (pm *PriorityOrdMap[K, V]) BulkSet(entries ...Entry[K, V]) {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	for _, entry := range entries {
+		if idx, ok := pm.search(entry.Key); ok {
+			pm.data[idx].entry.Value = entry.Value
+			continue
+		}
+
+		pm.insert(entry, 0)
+	}
+}
+
+// SetPriority sets a key/value pair along with its priority. If key is already present with a different priority, it
+// moves behind every other entry of its new priority; with the same priority, it keeps its position.
+func (pm *PriorityOrdMap[K, V]) SetPriority(key K, val V, priority int) {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if idx, ok := pm.search(key); ok {
+		if pm.data[idx].rank.priority == priority {
+			pm.data[idx].entry.Value = val
+			return
+		}
+
+		pm.remove(idx)
+	}
+
+	pm.insert(Entry[K, V]{Key: key, Value: val}, priority)
+}
+
+// Reprioritize changes the priority of key, moving it behind every other entry of its new priority. It returns false
+// if key isn't present.
+func (pm *PriorityOrdMap[K, V]) Reprioritize(key K, priority int) bool {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	idx, ok := pm.search(key)
+	if !ok {
+		return false
+	}
+
+	if pm.data[idx].rank.priority == priority {
+		return true
+	}
+
+	entry := pm.data[idx].entry
+	pm.remove(idx)
+	pm.insert(entry, priority)
+	return true
+}
+
+// Delete a key from the map.
+func (pm *PriorityOrdMap[K, V]) Delete(key K) {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if idx, ok := pm.search(key); ok {
+		pm.remove(idx)
+	}
+}
+
+// insert adds an entry behind every other entry of the same priority. The write lock must be held by the caller.
+func (pm *PriorityOrdMap[K, V]) insert(entry Entry[K, V], priority int) {
+	pm.seq++
+	rank := priorityRank{priority: priority, seq: pm.seq}
+	idx, _ := slices.BinarySearchFunc(pm.data, rank, func(p prioritized[K, V], rank priorityRank) int {
+		return p.rank.compare(rank)
+	})
+
+	pm.data = slices.Insert(pm.data, idx, prioritized[K, V]{entry: entry, rank: rank})
+	pm.rank[entry.Key] = rank
+}
+
+// remove deletes the entry at idx. The write lock must be held by the caller.
+func (pm *PriorityOrdMap[K, V]) remove(idx int) {
+	delete(pm.rank, pm.data[idx].entry.Key)
+	pm.data = slices.Delete(pm.data, idx, idx+1)
+}
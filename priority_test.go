@@ -0,0 +1,49 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_PriorityOrdMap(t *testing.T) {
+	pm := ordmap.NewPriority[string, int]()
+	pm.SetPriority("auth", 1, 10)
+	pm.SetPriority("log", 2, 0)
+	pm.SetPriority("metrics", 3, 10)
+	pm.Set("cache", 4)
+	pm.SetPriority("recover", 5, -10)
+
+	if got := fmt.Sprint(keys[string, int](&pm)); got != "[recover log cache auth metrics]" {
+		t.Fatalf("expected entries in priority order, got %s", got)
+	}
+
+	// updating a value keeps the position, while changing the priority moves to the back of the new priority
+	pm.SetPriority("log", 20, 0)
+	pm.Set("auth", 10)
+	if !pm.Reprioritize("recover", 10) {
+		t.Fatal("expected reprioritizing a present key to succeed")
+	}
+
+	if got := fmt.Sprint(pm.Entries()); got != "[{log 20} {cache 4} {auth 10} {metrics 3} {recover 5}]" {
+		t.Fatalf("unexpected entries after updates: %s", got)
+	}
+
+	if idx, _ := pm.Index("metrics"); idx != 3 {
+		t.Fatalf("expected metrics at index 3, got %d", idx)
+	}
+
+	if priority, _ := pm.Priority("recover"); priority != 10 {
+		t.Fatalf("expected recover to have priority 10, got %d", priority)
+	}
+
+	pm.Delete("cache")
+	if val, ok := pm.Get("cache"); ok || pm.Len() != 4 {
+		t.Fatalf("expected cache to be deleted, got %d, %t", val, ok)
+	}
+
+	if pm.Reprioritize("cache", 1) {
+		t.Fatal("expected reprioritizing a missing key to fail")
+	}
+}
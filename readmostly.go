@@ -0,0 +1,318 @@
+package ordmap
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// An rmEntry is a single entry within a ReadMostly map. A nil value marks the entry as deleted.
+type rmEntry[K comparable, V any] struct {
+	key K
+	val atomic.Pointer[V]
+}
+
+// load returns the entry's current value, if it hasn't been deleted.
+func (e *rmEntry[K, V]) load() (V, bool) {
+	p := e.val.Load()
+	if p == nil {
+		var zero V
+		return zero, false
+	}
+
+	return *p, true
+}
+
+// tryStore replaces the entry's value unless it has been deleted.
+func (e *rmEntry[K, V]) tryStore(val V) bool {
+	for {
+		p := e.val.Load()
+		if p == nil {
+			return false
+		}
+
+		if e.val.CompareAndSwap(p, &val) {
+			return true
+		}
+	}
+}
+
+// An rmState is an immutable read snapshot of a ReadMostly map's ordering. The entries themselves are shared with the
+// dirty overlay and later snapshots, which is what lets values be updated without republishing the snapshot.
+type rmState[K comparable, V any] struct {
+	lookup map[K]int
+	data   []*rmEntry[K, V]
+	// amended is true when the dirty overlay holds keys missing from this snapshot.
+	amended bool
+}
+
+// A ReadMostly is a concurrency safe ordered map tuned for workloads dominated by Gets of existing keys, following the
+// same read/dirty split as sync.Map. An immutable read snapshot serves lookups without locking, and updates or deletes
+// of keys already in the snapshot are applied atomically in place. Newly inserted keys land in a locked dirty overlay
+// which is promoted into a fresh snapshot once lookups have missed the snapshot as many times as the overlay has
+// entries, or when Promote is called. Keys deleted from the snapshot are dropped by the next promotion, which is
+// forced once they make up half of the snapshot.
+type ReadMostly[K comparable, V any] struct {
+	m      sync.Mutex
+	read   atomic.Pointer[rmState[K, V]]
+	length atomic.Int64
+
+	dirty       []*rmEntry[K, V]
+	dirtyLookup map[K]*rmEntry[K, V]
+	misses      int
+	// stale counts the entries of the read snapshot that have been deleted since it was published.
+	stale atomic.Int64
+}
+
+// NewReadMostly returns a new, empty ReadMostly map.
+func NewReadMostly[K comparable, V any]() ReadMostly[K, V] {
+	return ReadMostly[K, V]{
+		dirtyLookup: make(map[K]*rmEntry[K, V]),
+	}
+}
+
+// loadRead returns the currently published read snapshot.
+func (rm *ReadMostly[K, V]) loadRead() *rmState[K, V] {
+	if state := rm.read.Load(); state != nil {
+		return state
+	}
+
+	return &rmState[K, V]{}
+}
+
+// lookupRead returns the entry for key in the read snapshot, if there is one.
+func (rm *ReadMostly[K, V]) lookupRead(state *rmState[K, V], key K) (*rmEntry[K, V], bool) {
+	idx, ok := state.lookup[key]
+	if !ok {
+		return nil, false
+	}
+
+	return state.data[idx], true
+}
+
+// Entries returns a newly allocated, ordered slice of Entry structs which can be iterated on.
+func (rm *ReadMostly[K, V]) Entries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, rm.Len())
+	for key, val := range rm.All() {
+		entries = append(entries, Entry[K, V]{Key: key, Value: val})
+	}
+
+	return entries
+}
+
+// All returns an iterator over the map's key/value pairs in order. It is equivalent to AllCtx with a context that is
+// never cancelled.
+func (rm *ReadMostly[K, V]) All() iter.Seq2[K, V] {
+	return rm.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the map's key/value pairs in order. The set of keys is captured when iteration
+// starts, while values are read as they are reached. No locks are held while yielding, so the loop body may mutate the
+// map. Iteration stops as soon as ctx is cancelled.
+func (rm *ReadMostly[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		state := rm.loadRead()
+		entries := state.data
+		if state.amended {
+			rm.m.Lock()
+			state = rm.loadRead()
+			entries = make([]*rmEntry[K, V], 0, len(state.data)+len(rm.dirty))
+			entries = append(entries, state.data...)
+			entries = append(entries, rm.dirty...)
+			rm.m.Unlock()
+		}
+
+		for _, e := range entries {
+			if ctx.Err() != nil {
+				return
+			}
+
+			val, ok := e.load()
+			if !ok {
+				continue
+			}
+
+			if !yield(e.key, val) {
+				return
+			}
+		}
+	}
+}
+
+// Get implements a map lookup. Keys present in the read snapshot are served without locking.
+func (rm *ReadMostly[K, V]) Get(key K) (V, bool) {
+	state := rm.loadRead()
+	if e, ok := rm.lookupRead(state, key); ok {
+		if val, ok := e.load(); ok {
+			return val, true
+		}
+	}
+
+	if !state.amended {
+		var zero V
+		return zero, false
+	}
+
+	rm.m.Lock()
+	defer rm.m.Unlock()
+	e, ok := rm.dirtyLookup[key]
+	if !ok {
+		// the overlay may have been promoted while we waited for the lock
+		e, ok = rm.lookupRead(rm.loadRead(), key)
+	}
+
+	// like sync.Map, every lookup that had to take the lock counts as a miss, including lookups of absent keys
+	rm.missLocked()
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return e.load()
+}
+
+// Index returns the ordered index associated with the given key. This requires counting the live entries ahead of key
+// and is O(n).
+func (rm *ReadMostly[K, V]) Index(key K) (int, bool) {
+	idx := 0
+	for k := range rm.All() {
+		if k == key {
+			return idx, true
+		}
+		idx++
+	}
+
+	return 0, false
+}
+
+// Set a key/value pair within the map. Updating a key that is already in the read snapshot does not take the lock.
+func (rm *ReadMostly[K, V]) Set(key K, val V) {
+	if e, ok := rm.lookupRead(rm.loadRead(), key); ok && e.tryStore(val) {
+		return
+	}
+
+	rm.m.Lock()
+	defer rm.m.Unlock()
+	rm.setLocked(key, val)
+}
+
+// BulkSet allows for setting many entries at once while only locking the mutex once. In the case of duplicated keys,
+// earlier values in the list will be overwritten.
+func (rm *ReadMostly[K, V]) BulkSet(entries ...Entry[K, V]) {
+	rm.m.Lock()
+	defer rm.m.Unlock()
+	for _, entry := range entries {
+		rm.setLocked(entry.Key, entry.Value)
+	}
+}
+
+// setLocked stores a single key/value pair. The lock must be held by the caller.
+func (rm *ReadMostly[K, V]) setLocked(key K, val V) {
+	state := rm.loadRead()
+	if e, ok := rm.lookupRead(state, key); ok && e.tryStore(val) {
+		return
+	}
+
+	if e, ok := rm.dirtyLookup[key]; ok {
+		e.val.Store(&val)
+		return
+	}
+
+	e := &rmEntry[K, V]{key: key}
+	e.val.Store(&val)
+	rm.dirty = append(rm.dirty, e)
+	rm.dirtyLookup[key] = e
+	rm.length.Add(1)
+	if !state.amended {
+		rm.read.Store(&rmState[K, V]{lookup: state.lookup, data: state.data, amended: true})
+	}
+}
+
+// Has works the same as Get but does not return the value. It's included for convenience.
+func (rm *ReadMostly[K, V]) Has(key K) bool {
+	_, ok := rm.Get(key)
+	return ok
+}
+
+// Delete a key from the map. Deleting a key that is in the read snapshot does not take the lock, unless deleted keys
+// have come to make up half of the snapshot and it's time to replace it.
+func (rm *ReadMostly[K, V]) Delete(key K) {
+	state := rm.loadRead()
+	if e, ok := rm.lookupRead(state, key); ok {
+		if e.val.Swap(nil) != nil {
+			rm.length.Add(-1)
+			if rm.stale.Add(1)*2 > int64(len(state.data)) {
+				rm.Promote()
+			}
+
+			return
+		}
+	}
+
+	rm.m.Lock()
+	defer rm.m.Unlock()
+	e, ok := rm.dirtyLookup[key]
+	if !ok {
+		return
+	}
+
+	delete(rm.dirtyLookup, key)
+	if e.val.Swap(nil) != nil {
+		rm.length.Add(-1)
+	}
+}
+
+// Len returns the current length of the map without locking.
+func (rm *ReadMostly[K, V]) Len() int {
+	return int(rm.length.Load())
+}
+
+// Promote merges the dirty overlay into a new read snapshot immediately, rather than waiting for enough lookups to
+// miss the current snapshot. Keys deleted from the current snapshot are left out of the new one.
+func (rm *ReadMostly[K, V]) Promote() {
+	rm.m.Lock()
+	defer rm.m.Unlock()
+	rm.promoteLocked()
+}
+
+// missLocked records a lookup that had to fall back to the dirty overlay and promotes the overlay once misses have
+// paid for the cost of copying it. The lock must be held by the caller.
+func (rm *ReadMostly[K, V]) missLocked() {
+	rm.misses++
+	if rm.misses >= len(rm.dirty) {
+		rm.promoteLocked()
+	}
+}
+
+// promoteLocked publishes a new read snapshot containing every live entry. The lock must be held by the caller.
+func (rm *ReadMostly[K, V]) promoteLocked() {
+	rm.misses = 0
+	state := rm.loadRead()
+	if !state.amended && rm.stale.Load() == 0 {
+		return
+	}
+
+	// reset before copying, so a delete racing with the copy is counted against the new snapshot rather than lost
+	rm.stale.Store(0)
+
+	next := &rmState[K, V]{
+		lookup: make(map[K]int, rm.length.Load()),
+		data:   make([]*rmEntry[K, V], 0, rm.length.Load()),
+	}
+
+	for _, entries := range [][]*rmEntry[K, V]{state.data, rm.dirty} {
+		for _, e := range entries {
+			if e.val.Load() == nil {
+				continue
+			}
+
+			next.lookup[e.key] = len(next.data)
+			next.data = append(next.data, e)
+		}
+	}
+
+	rm.read.Store(next)
+	rm.dirty = nil
+	clear(rm.dirtyLookup)
+}
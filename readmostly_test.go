@@ -0,0 +1,151 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_ReadMostlyLifecycle(t *testing.T) {
+	rm := ordmap.NewReadMostly[string, int]()
+	for i := 0; i < 5; i++ {
+		rm.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	rm.Promote()
+	rm.Set("key 1", 10)
+	rm.Delete("key 2")
+	rm.Set("key 5", 5)
+	rm.Set("key 2", 2)
+
+	expected := []ordmap.Entry[string, int]{
+		{Key: "key 0", Value: 0},
+		{Key: "key 1", Value: 10},
+		{Key: "key 3", Value: 3},
+		{Key: "key 4", Value: 4},
+		{Key: "key 5", Value: 5},
+		{Key: "key 2", Value: 2},
+	}
+
+	check := func() {
+		t.Helper()
+		entries := rm.Entries()
+		if len(entries) != len(expected) || rm.Len() != len(expected) {
+			t.Fatalf("expected %d entries, got %d (Len %d)", len(expected), len(entries), rm.Len())
+		}
+
+		for idx, entry := range entries {
+			if entry != expected[idx] {
+				t.Fatalf("expected entry #%d to be %+v, got %+v", idx, expected[idx], entry)
+			}
+
+			if val, ok := rm.Get(entry.Key); !ok || val != entry.Value {
+				t.Fatalf("expected %s to be %d, got %d", entry.Key, entry.Value, val)
+			}
+		}
+	}
+
+	check()
+	rm.Promote()
+	check()
+
+	if idx, ok := rm.Index("key 2"); !ok || idx != 5 {
+		t.Fatalf("expected key 2 to be at index 5, got %d", idx)
+	}
+}
+
+func Test_ReadMostlyConcurrentAccess(t *testing.T) {
+	rm := ordmap.NewReadMostly[string, int]()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			for j := 0; j < 1000; j++ {
+				key := fmt.Sprintf("%d", j)
+				rm.Set(key, idx*j)
+				rm.Get(key)
+				if j%3 == 0 {
+					rm.Delete(key)
+					rm.Set(key, j)
+				}
+			}
+			wg.Done()
+		}(i)
+	}
+
+	wg.Wait()
+	if rm.Len() != 1000 || len(rm.Entries()) != 1000 {
+		t.Fatalf("expected final map length to be 1000, got %d", rm.Len())
+	}
+}
+
+// A trackedKey is large enough to get its own allocation, so it can be collected independently of other keys.
+type trackedKey = *[4]int
+
+// trackKeys returns n new keys along with a counter of how many of them have been garbage collected.
+func trackKeys(n int) ([]trackedKey, *atomic.Int64) {
+	collected := new(atomic.Int64)
+	keys := make([]trackedKey, n)
+	for i := range keys {
+		keys[i] = new([4]int)
+		runtime.AddCleanup(keys[i], func(collected *atomic.Int64) { collected.Add(1) }, collected)
+	}
+
+	return keys, collected
+}
+
+// waitCollected runs the garbage collector until n keys have been collected, or fails after a while.
+func waitCollected(t *testing.T, collected *atomic.Int64, n int64) {
+	t.Helper()
+	for range 100 {
+		runtime.GC()
+		if collected.Load() >= n {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("expected %d keys to be released, only %d were", n, collected.Load())
+}
+
+func Test_ReadMostlyReclaimsDeletes(t *testing.T) {
+	rm := ordmap.NewReadMostly[trackedKey, int]()
+	keys, collected := trackKeys(100)
+	for _, key := range keys {
+		rm.Set(key, key[0])
+	}
+
+	rm.Promote()
+	for _, key := range keys {
+		rm.Delete(key)
+	}
+
+	keys = nil
+	waitCollected(t, collected, 100)
+	if rm.Len() != 0 {
+		t.Fatalf("expected the map to be empty, got %d", rm.Len())
+	}
+}
+
+func Test_ReadMostlyPromotesOnMisses(t *testing.T) {
+	rm := ordmap.NewReadMostly[trackedKey, int]()
+	keys, collected := trackKeys(1)
+	rm.Set(keys[0], 0)
+	rm.Delete(keys[0])
+	keys = nil
+
+	// lookups of keys found nowhere still count as misses, so the deleted overlay entry is eventually dropped
+	for range 10 {
+		rm.Get(new([4]int))
+	}
+
+	waitCollected(t, collected, 1)
+	runtime.KeepAlive(&rm)
+}
@@ -0,0 +1,48 @@
+package ordmap
+
+import "time"
+
+// WithRef calls fn with a pointer to the value stored at key while holding the write lock, so that large values can
+// be modified in place instead of being copied out with Get and back in with Set. It returns false without calling fn
+// if key isn't present. The pointer must not be kept after fn returns.
+//
+// The modification counts as a Set: it's recorded in the change log, reported to watchers and hooks, and moves the
+// entry for access ordered maps, but unlike Set it keeps any TTL. Maps with a journal or secondary indexes copy the old
+// value first, since both need it.
+func (om *OrdMap[K, V]) WithRef(key K, fn func(v *V)) bool {
+	key = om.normalize(key)
+	om.m.Lock()
+	defer om.unlock()
+	idx, ok := om.lookup[key]
+	if !ok || om.expired(key, time.Now()) {
+		return false
+	}
+
+	om.unshare()
+	var prev V
+	if om.journal != nil || len(om.indexes) > 0 {
+		prev = om.data[idx].Value
+	}
+
+	fn(&om.data[idx].Value)
+	entry := om.data[idx]
+	if om.journal != nil {
+		om.journalSet(entry, prev, true)
+	}
+
+	if len(om.indexes) > 0 {
+		om.removeFromIndexes(key, prev)
+		om.addToIndexes(key, entry.Value)
+	}
+
+	om.record(Change[K, V]{Op: OpSet, Key: key, Value: entry.Value})
+	if om.opts.policy != nil && !om.isPinned(key) {
+		om.opts.policy.OnSet(key)
+	}
+
+	if om.opts.accessOrder || om.opts.moveOnUpdate {
+		om.moveToBack(idx)
+	}
+
+	return true
+}
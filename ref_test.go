@@ -0,0 +1,46 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+type counter struct {
+	hits    int
+	history [64]int
+}
+
+func Test_WithRef(t *testing.T) {
+	om := ordmap.New(0, ordmap.WithJournal[string, counter](10))
+	om.Set("a", counter{})
+	snap := om.Snapshot()
+
+	for i := range 3 {
+		ok := om.WithRef("a", func(c *counter) {
+			c.history[c.hits] = i
+			c.hits++
+		})
+
+		if !ok {
+			t.Fatal("expected WithRef on a present key to succeed")
+		}
+	}
+
+	if c, _ := om.Get("a"); c.hits != 3 || c.history[2] != 2 {
+		t.Fatalf("expected 3 in place updates, got %d", c.hits)
+	}
+
+	if c, _ := snap.Get("a"); c.hits != 0 {
+		t.Fatal("expected WithRef not to modify an earlier snapshot")
+	}
+
+	om.Undo(1)
+	if c, _ := om.Get("a"); c.hits != 2 {
+		t.Fatalf("expected undo to revert the last update, got %d hits", c.hits)
+	}
+
+	if om.WithRef("missing", func(*counter) { t.Fatal("expected fn not to be called") }) {
+		t.Fatal("expected WithRef on a missing key to fail")
+	}
+}
@@ -0,0 +1,39 @@
+package ordmap
+
+import "time"
+
+// KeyOf returns the first key in om's order whose value equals val, or false if no value does. It's an O(n) scan
+// under a single read lock, meant for occasional reverse lookups; use an OrdBiMap when they're frequent.
+func KeyOf[K, V comparable](om *OrdMap[K, V], val V) (K, bool) {
+	return om.keyFunc(func(_ K, v V) bool {
+		return v == val
+	})
+}
+
+// ContainsValue returns whether any key in om maps to val. Like KeyOf, it's O(n).
+func ContainsValue[K, V comparable](om *OrdMap[K, V], val V) bool {
+	_, ok := KeyOf(om, val)
+	return ok
+}
+
+// ContainsFunc returns whether fn returns true for any of the OrdMap's entries, checking them in order and stopping at
+// the first match. It's an O(n) scan under a single read lock, so fn must not mutate the OrdMap.
+func (om *OrdMap[K, V]) ContainsFunc(fn func(key K, val V) bool) bool {
+	_, ok := om.keyFunc(fn)
+	return ok
+}
+
+// keyFunc returns the first key in order for which fn returns true.
+func (om *OrdMap[K, V]) keyFunc(fn func(key K, val V) bool) (K, bool) {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	now := time.Now()
+	for idx := om.front; idx < len(om.data); idx++ {
+		if om.visible(idx, now) && fn(om.data[idx].Key, om.data[idx].Value) {
+			return om.data[idx].Key, true
+		}
+	}
+
+	var zero K
+	return zero, false
+}
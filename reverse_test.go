@@ -0,0 +1,35 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_KeyOf(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 2)
+
+	if key, ok := ordmap.KeyOf(&om, 2); !ok || key != "b" {
+		t.Fatalf("expected the first key with value 2 to be b, got %q", key)
+	}
+
+	om.Delete("b")
+	if key, _ := ordmap.KeyOf(&om, 2); key != "c" {
+		t.Fatalf("expected c once b is deleted, got %q", key)
+	}
+
+	if ordmap.ContainsValue(&om, 3) {
+		t.Fatal("expected no key to map to 3")
+	}
+
+	if !om.ContainsFunc(func(key string, val int) bool { return val > 1 }) {
+		t.Fatal("expected a value greater than 1")
+	}
+
+	if om.ContainsFunc(func(key string, val int) bool { return key == "b" }) {
+		t.Fatal("expected deleted keys not to be matched")
+	}
+}
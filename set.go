@@ -0,0 +1,143 @@
+package ordmap
+
+import (
+	"context"
+	"iter"
+)
+
+// An OrdSet is a concurrency safe, insertion ordered set built on the same machinery as OrdMap.
+type OrdSet[K comparable] struct {
+	om OrdMap[K, struct{}]
+}
+
+// NewSet returns a new OrdSet containing keys, in order.
+func NewSet[K comparable](keys ...K) OrdSet[K] {
+	entries := make([]Entry[K, struct{}], 0, len(keys))
+	lookup := make(map[K]int, len(keys))
+	for _, key := range keys {
+		if _, ok := lookup[key]; ok {
+			continue
+		}
+
+		lookup[key] = len(entries)
+		entries = append(entries, Entry[K, struct{}]{Key: key})
+	}
+
+	return setOf(entries, lookup)
+}
+
+// setOf wraps already deduplicated entries and their lookup map in a new OrdSet.
+func setOf[K comparable](entries []Entry[K, struct{}], lookup map[K]int) (s OrdSet[K]) {
+	s.om.lookup, s.om.data, s.om.peak = lookup, entries, len(lookup)
+	s.om.length.Store(int64(len(lookup)))
+	return
+}
+
+// Add inserts keys at the end of the ordering. Keys that are already present keep their position.
+func (s *OrdSet[K]) Add(keys ...K) {
+	entries := make([]Entry[K, struct{}], len(keys))
+	for idx, key := range keys {
+		entries[idx].Key = key
+	}
+
+	s.om.BulkSet(entries...)
+}
+
+// Has reports whether key is a member of the OrdSet.
+func (s *OrdSet[K]) Has(key K) bool {
+	return s.om.Has(key)
+}
+
+// Delete removes key from the OrdSet.
+func (s *OrdSet[K]) Delete(key K) {
+	s.om.Delete(key)
+}
+
+// Len returns the number of members in the OrdSet.
+func (s *OrdSet[K]) Len() int {
+	return s.om.Len()
+}
+
+// Index returns the ordered index associated with the given key.
+func (s *OrdSet[K]) Index(key K) (int, bool) {
+	return s.om.Index(key)
+}
+
+// Keys returns a newly allocated, ordered slice of the OrdSet's members.
+func (s *OrdSet[K]) Keys() []K {
+	entries := s.om.snapshot()
+	keys := make([]K, len(entries))
+	for idx, entry := range entries {
+		keys[idx] = entry.Key
+	}
+
+	return keys
+}
+
+// All returns an iterator over the OrdSet's members in order.
+func (s *OrdSet[K]) All() iter.Seq[K] {
+	return s.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the OrdSet's members in order. Iteration stops as soon as ctx is cancelled. The read
+// lock is held until iteration finishes or stops, so the loop body must not mutate the same OrdSet.
+func (s *OrdSet[K]) AllCtx(ctx context.Context) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range s.om.AllCtx(ctx) {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Union returns a new OrdSet holding the members of s followed by the members of other that aren't in s.
+func (s *OrdSet[K]) Union(other *OrdSet[K]) OrdSet[K] {
+	theirs := other.om.snapshot()
+	entries := s.om.snapshot()
+	lookup := make(map[K]int, len(entries)+len(theirs))
+	for idx, entry := range entries {
+		lookup[entry.Key] = idx
+	}
+
+	for _, entry := range theirs {
+		if _, ok := lookup[entry.Key]; !ok {
+			lookup[entry.Key] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+
+	return setOf(entries, lookup)
+}
+
+// Intersect returns a new OrdSet holding the members of s that are also in other, in the order of s.
+func (s *OrdSet[K]) Intersect(other *OrdSet[K]) OrdSet[K] {
+	return s.filter(other, true)
+}
+
+// Difference returns a new OrdSet holding the members of s that are not in other, in the order of s.
+func (s *OrdSet[K]) Difference(other *OrdSet[K]) OrdSet[K] {
+	return s.filter(other, false)
+}
+
+// filter returns a new OrdSet of the members of s whose membership in other matches keep. The sets are snapshotted
+// one at a time so their locks are never held together.
+func (s *OrdSet[K]) filter(other *OrdSet[K], keep bool) OrdSet[K] {
+	theirs := other.om.snapshot()
+	members := make(map[K]struct{}, len(theirs))
+	for _, entry := range theirs {
+		members[entry.Key] = struct{}{}
+	}
+
+	entries := s.om.snapshot()
+	lookup := make(map[K]int, len(entries))
+	filtered := entries[:0]
+	for _, entry := range entries {
+		if _, ok := members[entry.Key]; ok == keep {
+			lookup[entry.Key] = len(filtered)
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return setOf(filtered, lookup)
+}
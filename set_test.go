@@ -0,0 +1,44 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Set(t *testing.T) {
+	a := ordmap.NewSet(5, 3, 1, 3)
+	a.Add(7, 5)
+	b := ordmap.NewSet(7, 2, 3)
+
+	if fmt.Sprint(a.Keys()) != "[5 3 1 7]" || !a.Has(1) || a.Len() != 4 {
+		t.Fatalf("unexpected members %v", a.Keys())
+	}
+
+	union := a.Union(&b)
+	if fmt.Sprint(union.Keys()) != "[5 3 1 7 2]" {
+		t.Fatalf("unexpected union %v", union.Keys())
+	}
+
+	intersect := a.Intersect(&b)
+	if fmt.Sprint(intersect.Keys()) != "[3 7]" {
+		t.Fatalf("unexpected intersection %v", intersect.Keys())
+	}
+
+	diff := a.Difference(&b)
+	if fmt.Sprint(diff.Keys()) != "[5 1]" {
+		t.Fatalf("unexpected difference %v", diff.Keys())
+	}
+
+	diff.Delete(5)
+	diff.Add(9)
+	var keys []int
+	for key := range diff.All() {
+		keys = append(keys, key)
+	}
+
+	if fmt.Sprint(keys) != "[1 9]" || a.Len() != 4 {
+		t.Fatalf("expected derived sets to be independent, got %v", keys)
+	}
+}
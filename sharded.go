@@ -0,0 +1,207 @@
+package ordmap
+
+import (
+	"context"
+	"hash/maphash"
+	"iter"
+	"sync/atomic"
+)
+
+// A sequenced value carries the global insertion sequence of an entry stored within a shard.
+type sequenced[V any] struct {
+	seq uint64
+	val V
+}
+
+// A Sharded is a concurrency safe ordered map that spreads its keys across a fixed number of independently locked
+// shards, so writers touching different keys rarely contend with each other. Every new key is stamped with a global
+// insertion sequence, and iteration merges the shards back together in that order. Iteration is consistent per shard
+// but not across shards: writes landing in a shard that has already been read during an iteration are not observed.
+type Sharded[K comparable, V any] struct {
+	seed   maphash.Seed
+	seq    atomic.Uint64
+	shards []*OrdMap[K, sequenced[V]]
+}
+
+// NewSharded returns a new Sharded map with the given number of shards. A shards value less than 1 is treated as 1.
+func NewSharded[K comparable, V any](shards int) Sharded[K, V] {
+	if shards < 1 {
+		shards = 1
+	}
+
+	segments := make([]*OrdMap[K, sequenced[V]], shards)
+	for idx := range segments {
+		shard := New[K, sequenced[V]](0)
+		segments[idx] = &shard
+	}
+
+	return Sharded[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: segments,
+	}
+}
+
+// shard returns the shard responsible for key.
+func (sm *Sharded[K, V]) shard(key K) *OrdMap[K, sequenced[V]] {
+	return sm.shards[sm.shardIndex(key)]
+}
+
+// shardIndex returns the index of the shard responsible for key.
+func (sm *Sharded[K, V]) shardIndex(key K) int {
+	if len(sm.shards) == 1 {
+		return 0
+	}
+
+	return int(maphash.Comparable(sm.seed, key) % uint64(len(sm.shards)))
+}
+
+// Entries returns a newly allocated, ordered slice of Entry structs which can be iterated on.
+func (sm *Sharded[K, V]) Entries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, sm.Len())
+	for key, val := range sm.All() {
+		entries = append(entries, Entry[K, V]{Key: key, Value: val})
+	}
+
+	return entries
+}
+
+// All returns an iterator over the map's key/value pairs in order. It is equivalent to AllCtx with a context that is
+// never cancelled.
+func (sm *Sharded[K, V]) All() iter.Seq2[K, V] {
+	return sm.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the map's key/value pairs in insertion order. Each shard is snapshotted before
+// iteration starts, so no locks are held while yielding and the loop body may mutate the map. Iteration stops as soon
+// as ctx is cancelled.
+func (sm *Sharded[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		segments := make([][]Entry[K, sequenced[V]], len(sm.shards))
+		for idx, shard := range sm.shards {
+			segments[idx] = shard.snapshot()
+		}
+
+		// a k-way merge by sequence number, which is cheap for the small shard counts this type is intended for
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+
+			next := -1
+			for idx, segment := range segments {
+				if len(segment) == 0 {
+					continue
+				}
+
+				if next == -1 || segment[0].Value.seq < segments[next][0].Value.seq {
+					next = idx
+				}
+			}
+
+			if next == -1 {
+				return
+			}
+
+			entry := segments[next][0]
+			segments[next] = segments[next][1:]
+			if !yield(entry.Key, entry.Value.val) {
+				return
+			}
+		}
+	}
+}
+
+// Get implements a map lookup. This should semantically be O(1) and equivalent to val, ok := map[key].
+func (sm *Sharded[K, V]) Get(key K) (V, bool) {
+	val, ok := sm.shard(key).Get(key)
+	return val.val, ok
+}
+
+// Index returns the ordered index associated with the given key. This requires counting the older entries in every
+// shard and is O(n).
+func (sm *Sharded[K, V]) Index(key K) (int, bool) {
+	target, ok := sm.shard(key).Get(key)
+	if !ok {
+		return 0, false
+	}
+
+	idx := 0
+	for _, shard := range sm.shards {
+		for _, val := range shard.All() {
+			if val.seq < target.seq {
+				idx++
+			}
+		}
+	}
+
+	return idx, true
+}
+
+// Set a key/value pair within the map. Only the shard owning key is locked.
+func (sm *Sharded[K, V]) Set(key K, val V) {
+	sm.setShard(sm.shard(key), Entry[K, V]{Key: key, Value: val})
+}
+
+// BulkSet allows for setting many entries at once. Every shard touched by entries is locked once for the whole
+// operation, so ordering between new keys matches the input. In the case of duplicated keys, earlier values in the
+// list will be overwritten.
+func (sm *Sharded[K, V]) BulkSet(entries ...Entry[K, V]) {
+	touched := make([]bool, len(sm.shards))
+	for _, entry := range entries {
+		touched[sm.shardIndex(entry.Key)] = true
+	}
+
+	// shards are always locked in index order so concurrent bulk sets can't deadlock
+	for idx, shard := range sm.shards {
+		if touched[idx] {
+			shard.m.Lock()
+			defer shard.m.Unlock()
+		}
+	}
+
+	for _, entry := range entries {
+		sm.setLocked(sm.shard(entry.Key), entry)
+	}
+}
+
+// setShard stores a single entry in shard while holding its write lock.
+func (sm *Sharded[K, V]) setShard(shard *OrdMap[K, sequenced[V]], entry Entry[K, V]) {
+	shard.m.Lock()
+	defer shard.m.Unlock()
+	sm.setLocked(shard, entry)
+}
+
+// setLocked stores entry in shard. Sequence numbers for new keys are only allocated while the shard's write lock is
+// held, which guarantees they increase in slice order within a shard. The shard's write lock must be held by the
+// caller.
+func (sm *Sharded[K, V]) setLocked(shard *OrdMap[K, sequenced[V]], entry Entry[K, V]) {
+	var seq uint64
+	if idx, ok := shard.lookup[entry.Key]; ok {
+		seq = shard.data[idx].Value.seq
+	} else {
+		seq = sm.seq.Add(1)
+	}
+
+	shard.set(Entry[K, sequenced[V]]{Key: entry.Key, Value: sequenced[V]{seq: seq, val: entry.Value}})
+}
+
+// Has works the same as Get but does not return the value. It's included for convenience.
+func (sm *Sharded[K, V]) Has(key K) bool {
+	return sm.shard(key).Has(key)
+}
+
+// Delete a key from the map. Only the shard owning key is locked.
+func (sm *Sharded[K, V]) Delete(key K) {
+	sm.shard(key).Delete(key)
+}
+
+// Len returns the current length of the map. Shards are read one at a time, so the result may not reflect a single
+// point in time when writers are active.
+func (sm *Sharded[K, V]) Len() int {
+	total := 0
+	for _, shard := range sm.shards {
+		total += shard.Len()
+	}
+
+	return total
+}
@@ -0,0 +1,72 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_ShardedOrder(t *testing.T) {
+	sm := ordmap.NewSharded[string, int](8)
+
+	entries := make([]ordmap.Entry[string, int], 1000)
+	for idx := range entries {
+		entries[idx] = ordmap.Entry[string, int]{Key: fmt.Sprintf("key %d", idx), Value: idx}
+	}
+
+	sm.BulkSet(entries[:500]...)
+	for _, entry := range entries[500:] {
+		sm.Set(entry.Key, entry.Value)
+	}
+
+	sm.Delete("key 10")
+	sm.Set("key 20", -20)
+
+	idx := 0
+	for key, val := range sm.All() {
+		expected := idx
+		if idx >= 10 {
+			expected++
+		}
+
+		want := expected
+		if expected == 20 {
+			want = -20
+		}
+
+		if key != fmt.Sprintf("key %d", expected) || val != want {
+			t.Fatalf("expected entry #%d to be key %d=%d, received key=%s val=%d", idx, expected, want, key, val)
+		}
+		idx++
+	}
+
+	if sm.Len() != 999 {
+		t.Fatalf("expected map length to be 999, got %d", sm.Len())
+	}
+
+	if pos, ok := sm.Index("key 500"); !ok || pos != 499 {
+		t.Fatalf("expected key 500 to be at index 499, got %d", pos)
+	}
+}
+
+func Test_ShardedConcurrentAccess(t *testing.T) {
+	sm := ordmap.NewSharded[string, int](16)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			for j := 0; j < 1000; j++ {
+				sm.Set(fmt.Sprintf("%d", j), idx*j)
+			}
+			wg.Done()
+		}(i)
+	}
+
+	wg.Wait()
+	if sm.Len() != 1000 {
+		t.Fatalf("expected final map length to be 1000, got %d", sm.Len())
+	}
+}
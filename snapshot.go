@@ -0,0 +1,41 @@
+package ordmap
+
+import (
+	"maps"
+	"time"
+)
+
+// Snapshot returns an immutable view of the OrdMap as of this moment in O(1), without copying anything. The view keeps
+// sharing the OrdMap's storage until the next write, which is the one to pay for copying it, so a reader can walk a
+// snapshot for as long as it likes without holding up writers. Pending tombstones are compacted before the snapshot is
+// taken. Entries with a TTL are captured as they are, even if they expire later, but entries that have already expired
+// are left out as they are from Get and Entries. Since those can't be left out of shared storage, a snapshot taken
+// while any expired entries are waiting to be reaped copies the visible entries like Freeze does.
+//
+// Compared to Freeze, Snapshot moves the O(Len) copy from the reader to the first following writer, and skips it
+// entirely when no writes happen before the next snapshot.
+func (om *OrdMap[K, V]) Snapshot() Frozen[K, V] {
+	om.m.Lock()
+	defer om.m.Unlock()
+	if now := time.Now(); om.anyExpired(now) {
+		return frozenOf(om.visibleEntries(now))
+	}
+
+	om.sweep()
+	om.shared = true
+	return Frozen[K, V]{lookup: om.lookup, data: om.data[:len(om.data):len(om.data)]}
+}
+
+// unshare gives the OrdMap its own copies of data and lookup if they are currently shared with a snapshot. The write
+// lock must be held by the caller.
+func (om *OrdMap[K, V]) unshare() {
+	if !om.shared {
+		return
+	}
+
+	data := make([]Entry[K, V], len(om.data), cap(om.data))
+	copy(data, om.data)
+	om.data = data
+	om.lookup = maps.Clone(om.lookup)
+	om.shared = false
+}
@@ -0,0 +1,43 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Snapshot(t *testing.T) {
+	om := ordmap.New[string, int](16)
+	for i := 0; i < 5; i++ {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+	om.Delete("key 1")
+
+	snap := om.Snapshot()
+	iterated := 0
+	for key, val := range snap.All() {
+		// writers are free to continue while the snapshot is being read
+		om.Set(key, val*10)
+		om.Delete("key 3")
+		om.Set(fmt.Sprintf("new %d", iterated), iterated)
+		iterated++
+	}
+
+	if iterated != 4 || snap.Len() != 4 || !snap.Has("key 3") || snap.Has("new 0") {
+		t.Fatalf("expected snapshot to be unaffected by writes, got %v", snap.Entries())
+	}
+
+	if val, _ := snap.Get("key 2"); val != 2 {
+		t.Fatalf("expected snapshot to keep key 2=2, got %d", val)
+	}
+
+	if val, _ := om.Get("key 2"); val != 20 || om.Has("key 3") || om.Len() != 7 {
+		t.Fatalf("expected writes to apply to the map, got %v", om.Entries())
+	}
+
+	again := om.Snapshot()
+	if again.Len() != 7 {
+		t.Fatalf("expected new snapshot to see the latest writes, got %d entries", again.Len())
+	}
+}
@@ -0,0 +1,219 @@
+package ordmap
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+)
+
+// A SortedMap is a concurrency safe map whose ordering is defined by a comparison function on keys rather than by
+// insertion. Entries are kept in a sorted slice, so lookups and Index are O(log n) binary searches, iteration is as
+// fast as OrdMap's, and inserts and deletes are O(n) since they shift the slice. BulkSet should be preferred when
+// loading many entries at once.
+type SortedMap[K comparable, V any] struct {
+	m   sync.RWMutex
+	cmp func(a, b K) int
+
+	data []Entry[K, V]
+}
+
+// NewSorted returns a new, empty SortedMap ordered by cmp, which must return a negative number when a < b, a positive
+// number when a > b, and zero when they're equal.
+func NewSorted[K comparable, V any](cmp func(a, b K) int) SortedMap[K, V] {
+	return SortedMap[K, V]{cmp: cmp}
+}
+
+// NewSortedOrdered returns a new, empty SortedMap ordered by the natural ordering of its keys.
+func NewSortedOrdered[K cmp.Ordered, V any]() SortedMap[K, V] {
+	return NewSorted[K, V](cmp.Compare[K])
+}
+
+// search returns the position of key within data and whether it is present. The read lock must be held by the caller.
+func (sm *SortedMap[K, V]) search(key K) (int, bool) {
+	return slices.BinarySearchFunc(sm.data, key, func(entry Entry[K, V], key K) int {
+		return sm.cmp(entry.Key, key)
+	})
+}
+
+// Entries returns a newly allocated slice of the map's entries in key order.
+func (sm *SortedMap[K, V]) Entries() []Entry[K, V] {
+	sm.m.RLock()
+	defer sm.m.RUnlock()
+	return slices.Clone(sm.data)
+}
+
+// All returns an iterator over the map's key/value pairs in key order. It is equivalent to AllCtx with a context that
+// is never cancelled.
+func (sm *SortedMap[K, V]) All() iter.Seq2[K, V] {
+	return sm.AllCtx(context.Background())
+}
+
+// AllCtx returns an iterator over the map's key/value pairs in key order. Iteration stops as soon as ctx is
+// cancelled. The read lock is held until iteration finishes or stops, so the loop body must not mutate the same map.
+func (sm *SortedMap[K, V]) AllCtx(ctx context.Context) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		sm.m.RLock()
+		defer sm.m.RUnlock()
+		for _, entry := range sm.data {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Get implements an O(log n) map lookup.
+func (sm *SortedMap[K, V]) Get(key K) (V, bool) {
+	sm.m.RLock()
+	defer sm.m.RUnlock()
+	idx, ok := sm.search(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return sm.data[idx].Value, true
+}
+
+// Index returns the sorted index associated with the given key.
+func (sm *SortedMap[K, V]) Index(key K) (int, bool) {
+	sm.m.RLock()
+	defer sm.m.RUnlock()
+	idx, ok := sm.search(key)
+	if !ok {
+		return 0, false
+	}
+
+	return idx, true
+}
+
+// Set a key/value pair within the map, at the position dictated by its key.
+func (sm *SortedMap[K, V]) Set(key K, val V) {
+	sm.m.Lock()
+	defer sm.m.Unlock()
+	sm.set(Entry[K, V]{Key: key, Value: val})
+}
+
+// BulkSet allows for setting many entries at once. Small batches are inserted one at a time, while large batches are
+// appended and sorted in a single pass. In the case of duplicated keys, earlier values in the list will be
+// overwritten.
+func (sm *SortedMap[K, V]) BulkSet(entries ...Entry[K, V]) {
+	sm.m.Lock()
+	defer sm.m.Unlock()
+	if len(entries) < 16 {
+		for _, entry := range entries {
+			sm.set(entry)
+		}
+		return
+	}
+
+	// a stable sort keeps the existing entry ahead of any new entries for the same key, and the new entries in the
+	// order they were given, so keeping the last of each run of equal keys gives the right value
+	sm.data = append(sm.data, entries...)
+	slices.SortStableFunc(sm.data, func(a, b Entry[K, V]) int {
+		return sm.cmp(a.Key, b.Key)
+	})
+
+	deduped := sm.data[:0]
+	for idx, entry := range sm.data {
+		if idx+1 < len(sm.data) && sm.cmp(entry.Key, sm.data[idx+1].Key) == 0 {
+			continue
+		}
+
+		deduped = append(deduped, entry)
+	}
+
+	clear(sm.data[len(deduped):])
+	sm.data = deduped
+}
+
+// set stores a single entry. The write lock must be held by the caller.
+func (sm *SortedMap[K, V]) set(entry Entry[K, V]) {
+	idx, ok := sm.search(entry.Key)
+	if ok {
+		sm.data[idx].Value = entry.Value
+		return
+	}
+
+	sm.data = slices.Insert(sm.data, idx, entry)
+}
+
+// Has works the same as Get but does not return the value. It's included for convenience.
+func (sm *SortedMap[K, V]) Has(key K) bool {
+	sm.m.RLock()
+	_, ok := sm.search(key)
+	sm.m.RUnlock()
+	return ok
+}
+
+// Delete a key from the map.
+func (sm *SortedMap[K, V]) Delete(key K) {
+	sm.m.Lock()
+	defer sm.m.Unlock()
+	idx, ok := sm.search(key)
+	if !ok {
+		return
+	}
+
+	sm.data = slices.Delete(sm.data, idx, idx+1)
+}
+
+// Len returns the current length of the map.
+func (sm *SortedMap[K, V]) Len() int {
+	sm.m.RLock()
+	defer sm.m.RUnlock()
+	return len(sm.data)
+}
+
+// Range returns an iterator over the entries whose keys fall within [from, to), in key order. The bounds are located
+// with binary search, so only the matching entries are visited. The read lock is held until iteration finishes or
+// stops, so the loop body must not mutate the same map.
+func (sm *SortedMap[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		sm.m.RLock()
+		defer sm.m.RUnlock()
+		start, _ := sm.search(from)
+		for _, entry := range sm.data[start:] {
+			if sm.cmp(entry.Key, to) >= 0 {
+				return
+			}
+
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// RangeSorted returns an iterator over the entries of om whose keys fall within [from, to), using binary search to
+// find where the range starts. The caller asserts that om's keys were inserted in ascending order; if they weren't,
+// the results are undefined. The read lock is held until iteration finishes or stops, so the loop body must not
+// mutate om.
+func RangeSorted[K cmp.Ordered, V any](om *OrdMap[K, V], from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		om.m.RLock()
+		defer om.m.RUnlock()
+		// tombstones keep their key and position, so the slice stays sorted and can be searched as is
+		start, _ := slices.BinarySearchFunc(om.data, from, func(entry Entry[K, V], key K) int {
+			return cmp.Compare(entry.Key, key)
+		})
+
+		now := time.Now()
+		for idx := start; idx < len(om.data) && om.data[idx].Key < to; idx++ {
+			if !om.visible(idx, now) {
+				continue
+			}
+
+			if !yield(om.data[idx].Key, om.data[idx].Value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package ordmap_test
+
+import (
+	"cmp"
+	"fmt"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_SortedMap(t *testing.T) {
+	sm := ordmap.NewSortedOrdered[int, string]()
+	for _, key := range []int{5, 1, 4, 2, 3} {
+		sm.Set(key, fmt.Sprint(key))
+	}
+
+	sm.Set(3, "three")
+	sm.Delete(4)
+
+	var keys []int
+	for key := range sm.All() {
+		keys = append(keys, key)
+	}
+
+	if fmt.Sprint(keys) != "[1 2 3 5]" {
+		t.Fatalf("expected keys in sorted order, got %v", keys)
+	}
+
+	if val, ok := sm.Get(3); !ok || val != "three" {
+		t.Fatalf("expected 3 to be updated, got %s", val)
+	}
+
+	if idx, ok := sm.Index(5); !ok || idx != 3 {
+		t.Fatalf("expected 5 to be at index 3, got %d", idx)
+	}
+
+	entries := make([]ordmap.Entry[int, string], 0, 40)
+	for i := 40; i > 0; i-- {
+		entries = append(entries, ordmap.Entry[int, string]{Key: i % 20, Value: fmt.Sprint(i)})
+	}
+
+	sm.BulkSet(entries...)
+	if sm.Len() != 20 {
+		t.Fatalf("expected 20 distinct keys, got %d", sm.Len())
+	}
+
+	// later values win, so key 1 ends up with the value from i == 1
+	if val, _ := sm.Get(1); val != "1" {
+		t.Fatalf("expected the last value for 1 to win, got %s", val)
+	}
+
+	desc := ordmap.NewSorted[int, int](func(a, b int) int { return cmp.Compare(b, a) })
+	desc.BulkSet(ordmap.Entry[int, int]{Key: 1}, ordmap.Entry[int, int]{Key: 3}, ordmap.Entry[int, int]{Key: 2})
+	if entries := desc.Entries(); entries[0].Key != 3 || entries[2].Key != 1 {
+		t.Fatalf("expected custom comparator to sort descending, got %v", entries)
+	}
+}
+
+func Test_Range(t *testing.T) {
+	sm := ordmap.NewSortedOrdered[int, int]()
+	om := ordmap.New[int, int](0)
+	for i := 0; i < 100; i += 10 {
+		sm.Set(i, i)
+		om.Set(i, i)
+	}
+
+	om.Delete(40)
+
+	collect := func(seq func(func(int, int) bool)) []int {
+		var keys []int
+		for key := range seq {
+			keys = append(keys, key)
+		}
+		return keys
+	}
+
+	if keys := collect(sm.Range(25, 60)); fmt.Sprint(keys) != "[30 40 50]" {
+		t.Fatalf("unexpected range %v", keys)
+	}
+
+	if keys := collect(sm.Range(30, 30)); len(keys) != 0 {
+		t.Fatalf("expected empty range, got %v", keys)
+	}
+
+	if keys := collect(ordmap.RangeSorted(&om, 30, 70)); fmt.Sprint(keys) != "[30 50 60]" {
+		t.Fatalf("unexpected range %v", keys)
+	}
+}
+
+func Test_SortedEntriesCopies(t *testing.T) {
+	sm := ordmap.NewSortedOrdered[int, string]()
+	sm.BulkSet(ordmap.Entry[int, string]{Key: 1, Value: "one"}, ordmap.Entry[int, string]{Key: 3, Value: "three"})
+
+	entries := sm.Entries()
+	sm.Delete(1)
+	sm.Set(2, "two")
+	sm.BulkSet(ordmap.Entry[int, string]{Key: 0, Value: "zero"}, ordmap.Entry[int, string]{Key: 1, Value: "uno"})
+	if got := fmt.Sprint(entries); got != "[{1 one} {3 three}]" {
+		t.Fatalf("expected Entries not to observe later writes, got %s", got)
+	}
+}
@@ -0,0 +1,52 @@
+package ordmap
+
+import "unsafe"
+
+// Stats is a point in time summary of an OrdMap's size and memory usage.
+type Stats struct {
+	// Len is the number of live entries.
+	Len int
+	// Cap is the capacity of the underlying data slice.
+	Cap int
+	// Tombstones is the number of deleted entries still occupying slots in the data slice.
+	Tombstones int
+	// Wasted is the number of data slots not holding a live entry, including tombstones and unused capacity.
+	Wasted int
+	// LookupPeak is the number of keys the lookup map is sized for. Go maps never shrink, so this only goes down when
+	// the lookup map is rebuilt.
+	LookupPeak int
+	// ApproxBytes is a rough estimate of the memory held by the data slice and lookup map. It's shallow, so memory
+	// referenced by keys and values (such as string contents) is not included.
+	ApproxBytes uintptr
+}
+
+// Cap returns the capacity of the OrdMap's underlying data slice, which is the number of entries it can hold before
+// the slice has to be grown again. Tombstones count against this capacity until they are compacted.
+func (om *OrdMap[K, V]) Cap() int {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	return cap(om.data)
+}
+
+// Stats returns a summary of the OrdMap's size and memory usage, useful for sizing maps and spotting leaks.
+func (om *OrdMap[K, V]) Stats() Stats {
+	om.m.RLock()
+	defer om.m.RUnlock()
+
+	var (
+		key   K
+		entry Entry[K, V]
+	)
+
+	// lookup maps store a key, an int, and roughly a byte of control data per slot at a maximum load factor of 7/8
+	lookupSlot := unsafe.Sizeof(key) + unsafe.Sizeof(int(0)) + 1
+	live := len(om.data) - om.tombstones
+	return Stats{
+		Len:         live,
+		Cap:         cap(om.data),
+		Tombstones:  om.tombstones,
+		Wasted:      cap(om.data) - live,
+		LookupPeak:  om.peak,
+		ApproxBytes: unsafe.Sizeof(entry)*uintptr(cap(om.data)) + lookupSlot*uintptr(om.peak)*8/7,
+	}
+}
@@ -0,0 +1,30 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Stats(t *testing.T) {
+	om := ordmap.New[int, int](100)
+	for i := 0; i < 10; i++ {
+		om.Set(i, i)
+	}
+
+	om.Delete(3)
+	stats := om.Stats()
+	if stats.Len != 9 || stats.Cap != om.Cap() || stats.Cap < 100 || stats.Tombstones != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if stats.Wasted != stats.Cap-9 || stats.LookupPeak != 100 || stats.ApproxBytes == 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	om.Compact()
+	stats = om.Stats()
+	if stats.Cap != 9 || stats.Tombstones != 0 || stats.Wasted != 0 || stats.LookupPeak != 9 {
+		t.Fatalf("unexpected stats after compaction %+v", stats)
+	}
+}
@@ -0,0 +1,141 @@
+package ordmap
+
+import "time"
+
+// A reaper is the handle for a running background expiration goroutine.
+type reaper struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// SetWithTTL sets a key/value pair that expires once ttl has elapsed. Expired entries are hidden from Get, Has,
+// iteration, Entries, Snapshot, and WriteTo right away, but keep occupying space (and counting toward Len) until they
+// are removed by Reap, either called directly or from a reaper started with StartReaper. Setting the key again without
+// a TTL clears its deadline.
+func (om *OrdMap[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+	key = om.normalize(key)
+	om.m.Lock()
+	defer om.unlock()
+	om.set(Entry[K, V]{Key: key, Value: val})
+	if _, ok := om.lookup[key]; !ok {
+		// the entry was evicted immediately by a size bound
+		return
+	}
+
+	if om.expiries == nil {
+		om.expiries = make(map[K]time.Time)
+	}
+
+	om.expiries[key] = time.Now().Add(ttl)
+}
+
+// TTL returns the time remaining until key expires. It returns false if key is not present, has already expired, or
+// was set without a TTL.
+func (om *OrdMap[K, V]) TTL(key K) (time.Duration, bool) {
+	key = om.normalize(key)
+	om.m.RLock()
+	defer om.m.RUnlock()
+	deadline, ok := om.expiries[key]
+	if !ok {
+		return 0, false
+	}
+
+	remaining := time.Until(deadline)
+	return remaining, remaining > 0
+}
+
+// Reap removes every expired entry from the OrdMap. Reaped entries are passed to the eviction callback registered with
+// WithOnEvict.
+func (om *OrdMap[K, V]) Reap() {
+	om.m.Lock()
+	defer om.unlock()
+	now := time.Now()
+	for key, deadline := range om.expiries {
+		if deadline.After(now) || om.isPinned(key) {
+			continue
+		}
+
+		om.evict(key)
+	}
+}
+
+// StartReaper starts a background goroutine that calls Reap every interval, until StopReaper is called. Calling it
+// while a reaper is already running, or with an interval that isn't positive, has no effect. It panics for maps
+// created with NewUnsafe, since the reaper would use them from another goroutine.
+func (om *OrdMap[K, V]) StartReaper(interval time.Duration) {
+	if om.opts.unsafe {
+		panic("ordmap: StartReaper on an OrdMap created with NewUnsafe")
+	}
+
+	if interval <= 0 {
+		return
+	}
+
+	om.m.Lock()
+	defer om.m.Unlock()
+	if om.reaper != nil {
+		return
+	}
+
+	r := &reaper{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	om.reaper = r
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				om.Reap()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopReaper stops the background reaper, if one is running, and waits for it to exit.
+func (om *OrdMap[K, V]) StopReaper() {
+	om.m.Lock()
+	r := om.reaper
+	om.reaper = nil
+	om.m.Unlock()
+	if r == nil {
+		return
+	}
+
+	close(r.stop)
+	<-r.done
+}
+
+// expired reports whether key has a deadline that has passed. The read lock must be held by the caller.
+func (om *OrdMap[K, V]) expired(key K, now time.Time) bool {
+	if len(om.expiries) == 0 {
+		return false
+	}
+
+	deadline, ok := om.expiries[key]
+	return ok && !deadline.After(now) && !om.isPinned(key)
+}
+
+// anyExpired reports whether any entry has expired as of now without being reaped yet. The read lock must be held by
+// the caller.
+func (om *OrdMap[K, V]) anyExpired(now time.Time) bool {
+	for key := range om.expiries {
+		if om.expired(key, now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// visible reports whether the slot at idx holds a live entry that hasn't expired. The read lock must be held by the
+// caller.
+func (om *OrdMap[K, V]) visible(idx int, now time.Time) bool {
+	return om.live(idx) && !om.expired(om.data[idx].Key, now)
+}
@@ -0,0 +1,90 @@
+package ordmap_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_TTL(t *testing.T) {
+	var reaped []string
+	om := ordmap.New(0, ordmap.WithOnEvict(func(key string, _ int) {
+		reaped = append(reaped, key)
+	}))
+
+	om.SetWithTTL("short", 1, time.Millisecond)
+	om.SetWithTTL("long", 2, time.Hour)
+	om.SetWithTTL("cleared", 3, time.Millisecond)
+	om.Set("cleared", 3)
+	om.Set("forever", 4)
+
+	time.Sleep(5 * time.Millisecond)
+	if om.Has("short") {
+		t.Fatal("expected short to be hidden once expired")
+	}
+
+	if _, ok := om.Get("short"); ok {
+		t.Fatal("expected short to be hidden once expired")
+	}
+
+	if ttl, ok := om.TTL("long"); !ok || ttl <= time.Minute {
+		t.Fatalf("expected long to have roughly an hour left, got %s", ttl)
+	}
+
+	for key := range om.All() {
+		if key == "short" {
+			t.Fatal("expected iteration to skip expired entries")
+		}
+	}
+
+	om.Reap()
+	if om.Len() != 3 || len(reaped) != 1 || reaped[0] != "short" {
+		t.Fatalf("expected only short to be reaped, reaped %v leaving %d entries", reaped, om.Len())
+	}
+}
+
+func Test_Reaper(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.StartReaper(time.Millisecond)
+	defer om.StopReaper()
+
+	om.SetWithTTL("short", 1, time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for om.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected reaper to remove the expired entry")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_ExpiredHiddenEverywhere(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	om.Set("a", 1)
+	om.SetWithTTL("b", 2, time.Millisecond)
+	om.Set("c", 3)
+	time.Sleep(5 * time.Millisecond)
+
+	if got := fmt.Sprint(om.Entries()); got != "[{a 1} {c 3}]" {
+		t.Fatalf("expected Entries to hide the expired entry, got %s", got)
+	}
+
+	if got := fmt.Sprint(om.Snapshot().Entries()); got != "[{a 1} {c 3}]" {
+		t.Fatalf("expected Snapshot to hide the expired entry, got %s", got)
+	}
+
+	var buf bytes.Buffer
+	om.WriteTo(&buf)
+	restored := ordmap.New[string, int](0)
+	restored.ReadFrom(&buf)
+	if got := fmt.Sprint(restored.Entries()); got != "[{a 1} {c 3}]" {
+		t.Fatalf("expected WriteTo to skip the expired entry, got %s", got)
+	}
+
+	// a non-positive interval would make the ticker panic
+	om.StartReaper(0)
+	om.StopReaper()
+}
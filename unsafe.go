@@ -0,0 +1,63 @@
+package ordmap
+
+import "sync"
+
+// NewUnsafe returns a new, empty OrdMap that skips locking entirely. It's faster for maps owned by a single goroutine,
+// but must never be used from more than one goroutine at a time without external synchronization. Building with the
+// ordmapdebug build tag makes unsafe maps panic when they detect concurrent use, instead of silently corrupting.
+// Features that work on the map from a background goroutine, StartReaper and Buffered with WithFlushInterval, aren't
+// supported and panic when used on an unsafe map.
+func NewUnsafe[K comparable, V any](initialSize int, opts ...Option[K, V]) OrdMap[K, V] {
+	return New(initialSize, append([]Option[K, V]{withoutLocking[K, V]()}, opts...)...)
+}
+
+// withoutLocking disables the OrdMap's lock.
+func withoutLocking[K comparable, V any]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.unsafe = true
+	}
+}
+
+// A mutex is the lock guarding an OrdMap. When disabled for maps created with NewUnsafe, locking only runs the
+// misuse checks compiled in by the ordmapdebug build tag, which are no-ops otherwise.
+type mutex struct {
+	sync.RWMutex
+	disabled bool
+	checker  misuseChecker
+}
+
+func (m *mutex) Lock() {
+	if m.disabled {
+		m.checker.lock()
+		return
+	}
+
+	m.RWMutex.Lock()
+}
+
+func (m *mutex) Unlock() {
+	if m.disabled {
+		m.checker.unlock()
+		return
+	}
+
+	m.RWMutex.Unlock()
+}
+
+func (m *mutex) RLock() {
+	if m.disabled {
+		m.checker.rlock()
+		return
+	}
+
+	m.RWMutex.RLock()
+}
+
+func (m *mutex) RUnlock() {
+	if m.disabled {
+		m.checker.runlock()
+		return
+	}
+
+	m.RWMutex.RUnlock()
+}
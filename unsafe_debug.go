@@ -0,0 +1,45 @@
+//go:build ordmapdebug
+
+package ordmap
+
+import "sync/atomic"
+
+// A misuseChecker tracks who is inside an unsafe OrdMap, like the runtime does for built-in maps. The state is -1
+// while a writer is inside, the number of readers otherwise, and any overlap that would have needed a real lock
+// panics.
+type misuseChecker struct {
+	state atomic.Int64
+}
+
+func (c *misuseChecker) lock() {
+	if c.state.CompareAndSwap(0, -1) {
+		return
+	}
+
+	if c.state.Load() > 0 {
+		panic("ordmap: concurrent read and write of an OrdMap created with NewUnsafe")
+	}
+
+	panic("ordmap: concurrent writes to an OrdMap created with NewUnsafe")
+}
+
+func (c *misuseChecker) unlock() {
+	c.state.Store(0)
+}
+
+func (c *misuseChecker) rlock() {
+	for {
+		state := c.state.Load()
+		if state < 0 {
+			panic("ordmap: concurrent read and write of an OrdMap created with NewUnsafe")
+		}
+
+		if c.state.CompareAndSwap(state, state+1) {
+			return
+		}
+	}
+}
+
+func (c *misuseChecker) runlock() {
+	c.state.Add(-1)
+}
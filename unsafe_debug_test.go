@@ -0,0 +1,30 @@
+//go:build ordmapdebug
+
+package ordmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_NewUnsafeDetectsMisuse(t *testing.T) {
+	om := ordmap.NewUnsafe[string, int](0)
+	om.Set("a", 1)
+	om.Set("b", 2)
+
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "concurrent read and write") {
+			t.Fatalf("expected a concurrent misuse panic, got %q", msg)
+		}
+	}()
+
+	// writing while iterating overlaps a write with a read, the same as another goroutine writing would
+	for key := range om.All() {
+		om.Set(key, 0)
+	}
+
+	t.Fatal("expected writing during iteration to panic")
+}
@@ -0,0 +1,11 @@
+//go:build !ordmapdebug
+
+package ordmap
+
+// A misuseChecker does nothing unless built with the ordmapdebug build tag.
+type misuseChecker struct{}
+
+func (misuseChecker) lock()    {}
+func (misuseChecker) unlock()  {}
+func (misuseChecker) rlock()   {}
+func (misuseChecker) runlock() {}
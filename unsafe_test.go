@@ -0,0 +1,55 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_NewUnsafe(t *testing.T) {
+	om := ordmap.NewUnsafe[string, int](0)
+	for i := range 10 {
+		om.Set(fmt.Sprintf("key %d", i), i)
+	}
+
+	om.Delete("key 3")
+	if val, ok := om.Get("key 4"); !ok || val != 4 {
+		t.Fatalf("expected key 4 to be 4, got %d, %t", val, ok)
+	}
+
+	if om.Len() != 9 {
+		t.Fatalf("expected 9 entries, got %d", om.Len())
+	}
+
+	if err := om.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func Test_NewUnsafeRefusesBackgroundGoroutines(t *testing.T) {
+	om := ordmap.NewUnsafe[string, int](0)
+	for name, start := range map[string]func(){
+		"reaper": func() { om.StartReaper(time.Second) },
+		"buffer": func() { om.Buffered(ordmap.WithFlushInterval(time.Second)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected a panic")
+				}
+			}()
+
+			start()
+		})
+	}
+
+	// buffers that only flush on demand stay on the caller's goroutine
+	b := om.Buffered(ordmap.WithFlushSize(2))
+	b.Set("a", 1)
+	b.Close()
+	if !om.Has("a") {
+		t.Fatal("expected the buffer to flush on close")
+	}
+}
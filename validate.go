@@ -0,0 +1,57 @@
+package ordmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCorrupt is wrapped by every error returned from Validate.
+var ErrCorrupt = errors.New("ordmap: corrupt")
+
+// Validate checks the OrdMap's internal invariants: that every lookup entry points at a slot holding its key, that no
+// key occupies more than one live slot, and that the tombstone and length bookkeeping agree with the slots. It returns
+// an error wrapping ErrCorrupt describing the first violation found, or nil. Validate is O(Len) and intended for tests
+// and debug builds.
+func (om *OrdMap[K, V]) Validate() error {
+	om.m.RLock()
+	defer om.m.RUnlock()
+	if om.tombstones < 0 || om.tombstones > len(om.data) {
+		return fmt.Errorf("%w: %d tombstones in %d slots", ErrCorrupt, om.tombstones, len(om.data))
+	}
+
+	// since every lookup entry owns a distinct slot, this also rules out a key being live in more than one slot
+	if len(om.lookup) != len(om.data)-om.tombstones {
+		return fmt.Errorf("%w: %d lookup entries for %d slots with %d tombstones", ErrCorrupt, len(om.lookup),
+			len(om.data), om.tombstones)
+	}
+
+	if length := om.length.Load(); length != int64(len(om.lookup)) {
+		return fmt.Errorf("%w: length is %d but there are %d lookup entries", ErrCorrupt, length, len(om.lookup))
+	}
+
+	for key, idx := range om.lookup {
+		if idx < 0 || idx >= len(om.data) {
+			return fmt.Errorf("%w: key %v points at slot %d of %d", ErrCorrupt, key, idx, len(om.data))
+		}
+
+		if om.data[idx].Key != key {
+			return fmt.Errorf("%w: key %v points at slot %d holding key %v", ErrCorrupt, key, idx, om.data[idx].Key)
+		}
+
+		if idx < om.front {
+			return fmt.Errorf("%w: key %v points at slot %d before the front at %d", ErrCorrupt, key, idx, om.front)
+		}
+
+		if f := om.filter.Load(); f != nil && !f.mayContain(key) {
+			return fmt.Errorf("%w: key %v is missing from the key filter", ErrCorrupt, key)
+		}
+	}
+
+	for key := range om.expiries {
+		if _, ok := om.lookup[key]; !ok {
+			return fmt.Errorf("%w: orphaned expiry for key %v", ErrCorrupt, key)
+		}
+	}
+
+	return nil
+}
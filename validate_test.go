@@ -0,0 +1,31 @@
+package ordmap_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Validate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	om := ordmap.New[string, int](0)
+	for i := range 10000 {
+		key := fmt.Sprintf("key %d", rng.Intn(500))
+		switch rng.Intn(4) {
+		case 0:
+			om.Delete(key)
+		case 1:
+			om.SwapDelete(key)
+		case 2:
+			om.MoveToFront(key)
+		default:
+			om.Set(key, i)
+		}
+
+		if err := om.Validate(); err != nil {
+			t.Fatalf("unexpected error after %d operations: %v", i+1, err)
+		}
+	}
+}
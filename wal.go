@@ -0,0 +1,468 @@
+package ordmap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A WAL makes an OrdMap durable by appending every Set and Delete made through it to a write-ahead log before applying
+// it. Opening a WAL on the same directory later rebuilds the map by loading the latest snapshot and replaying the log
+// written since. Reads go straight to the OrdMap, but writes made to the OrdMap directly rather than through the WAL
+// aren't logged. A WAL is safe for concurrent use.
+//
+// The directory holds one snapshot written with WriteTo and one log per generation. Compacting writes the next
+// generation's snapshot and log and flushes them and the directory before removing the old generation, so a crash at
+// any point, including a power loss, leaves either the old or the new generation complete.
+type WAL[K comparable, V any] struct {
+	m    sync.Mutex
+	om   *OrdMap[K, V]
+	opts walOptions
+	dir  string
+	gen  uint64
+	log  *os.File
+	// records counts the records in the current log, for automatic compaction
+	records int
+	buf     []byte
+}
+
+// A WALOption configures a WAL.
+type WALOption func(*walOptions)
+
+// walOptions holds the configuration assembled from the WALOptions passed to OpenWAL.
+type walOptions struct {
+	compactEvery int
+	sync         bool
+}
+
+// WithCompactEvery compacts the WAL automatically once its log holds n records. Without it, the log only shrinks when
+// Compact is called. A failed automatic compaction doesn't fail the write that triggered it, since that write is
+// already durable; it's retried on the next write, and calling Compact reports the error.
+func WithCompactEvery(n int) WALOption {
+	return func(o *walOptions) {
+		o.compactEvery = n
+	}
+}
+
+// WithSyncWrites makes every write through the WAL call fsync before returning, so that acknowledged writes survive a
+// machine crash and not just a process crash. This is much slower.
+func WithSyncWrites() WALOption {
+	return func(o *walOptions) {
+		o.sync = true
+	}
+}
+
+// OpenWAL loads the durable state stored in dir into the OrdMap and returns a WAL for making further writes durable.
+// The directory is created if it doesn't exist. Keys and values are encoded with the OrdMap's codecs, which have to
+// stay the same between runs. A log ending in a partially written record, as left by a crash, is truncated to the last
+// complete one, but a damaged record anywhere else in the log is reported as an error wrapping ErrInvalidSnapshot.
+func (om *OrdMap[K, V]) OpenWAL(dir string, opts ...WALOption) (*WAL[K, V], error) {
+	var o walOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	w := &WAL[K, V]{om: om, opts: o, dir: dir}
+	gen, err := w.latest()
+	if err != nil {
+		return nil, err
+	}
+
+	w.gen = gen
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+
+	if err := w.clean(); err != nil {
+		w.log.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Set durably sets a key/value pair.
+func (w *WAL[K, V]) Set(key K, val V) error {
+	return w.append(Change[K, V]{Op: OpSet, Key: key, Value: val})
+}
+
+// Delete durably deletes a key.
+func (w *WAL[K, V]) Delete(key K) error {
+	return w.append(Change[K, V]{Op: OpDelete, Key: key})
+}
+
+// append logs a single operation and then applies it to the OrdMap. The WAL's lock keeps the log in the same order as
+// the writes made to the map.
+func (w *WAL[K, V]) append(change Change[K, V]) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.log == nil {
+		return os.ErrClosed
+	}
+
+	keyCodec, valueCodec := w.om.opts.codecs()
+	payload := append(w.buf[:0], byte(change.Op))
+	payload, err := appendField(payload, keyCodec, change.Key)
+	if err != nil {
+		return fmt.Errorf("encoding key: %w", err)
+	}
+
+	if change.Op == OpSet {
+		if payload, err = appendField(payload, valueCodec, change.Value); err != nil {
+			return fmt.Errorf("encoding value: %w", err)
+		}
+	}
+
+	// the record is its payload's length, a checksum of the length, the payload, and a checksum of the payload, written
+	// with a single call so a crash can only tear the final record. Checking the length separately means a damaged one
+	// can't be mistaken for a record torn by the end of the log.
+	record := binary.AppendUvarint(make([]byte, 0, len(payload)+binary.MaxVarintLen64+8), uint64(len(payload)))
+	record = binary.LittleEndian.AppendUint32(record, crc32.ChecksumIEEE(record))
+	record = append(record, payload...)
+	record = binary.LittleEndian.AppendUint32(record, crc32.ChecksumIEEE(payload))
+	w.buf = payload
+	if _, err := w.log.Write(record); err != nil {
+		return err
+	}
+
+	if w.opts.sync {
+		if err := w.log.Sync(); err != nil {
+			return err
+		}
+	}
+
+	w.om.Apply([]Change[K, V]{change})
+	w.records++
+	if w.opts.compactEvery > 0 && w.records >= w.opts.compactEvery {
+		// the write itself has succeeded, and records stays over the threshold so the next one tries again
+		_ = w.compact()
+	}
+
+	return nil
+}
+
+// Compact writes the OrdMap's current state as a new snapshot and starts a new, empty log.
+func (w *WAL[K, V]) Compact() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.log == nil {
+		return os.ErrClosed
+	}
+
+	return w.compact()
+}
+
+// compact starts the next generation. Until its snapshot is durably renamed into place, every failure is rolled back
+// so the current generation stays the one that's written to and recovered from. The WAL's lock must be held by the
+// caller.
+func (w *WAL[K, V]) compact() error {
+	next := w.gen + 1
+	snapshot := w.path("snapshot", next)
+	tmp := snapshot + ".tmp"
+	if err := w.writeSnapshot(tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// the log is created before the snapshot is published, so the new generation never exists without one
+	log, err := os.OpenFile(w.path("log", next), os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	abort := func(err error) error {
+		log.Close()
+		os.Remove(log.Name())
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, snapshot); err != nil {
+		return abort(err)
+	}
+
+	if err := syncDir(w.dir); err != nil {
+		// the rename may or may not survive a crash, so take it back rather than risk recovering from a generation
+		// that's missing writes made after this
+		os.Remove(snapshot)
+		return abort(err)
+	}
+
+	w.log.Close()
+	w.log, w.gen, w.records = log, next, 0
+	return w.clean()
+}
+
+// writeSnapshot writes the OrdMap to a new file at path and flushes it.
+func (w *WAL[K, V]) writeSnapshot(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.om.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Close closes the log. The OrdMap remains usable, but further writes through the WAL fail.
+func (w *WAL[K, V]) Close() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.log == nil {
+		return nil
+	}
+
+	err := w.log.Close()
+	w.log = nil
+	return err
+}
+
+// path returns the name of a generation's snapshot or log file.
+func (w *WAL[K, V]) path(kind string, gen uint64) string {
+	return filepath.Join(w.dir, fmt.Sprintf("%s-%020d", kind, gen))
+}
+
+// latest returns the newest generation with a complete snapshot, or zero if there isn't one.
+func (w *WAL[K, V]) latest() (uint64, error) {
+	names, err := filepath.Glob(filepath.Join(w.dir, "snapshot-*"))
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint64
+	for _, name := range names {
+		gen, err := strconv.ParseUint(strings.TrimPrefix(filepath.Base(name), "snapshot-"), 10, 64)
+		if err == nil {
+			latest = max(latest, gen)
+		}
+	}
+
+	return latest, nil
+}
+
+// load reads the current generation's snapshot, if it has one, replays its log, and opens the log for appending.
+func (w *WAL[K, V]) load() error {
+	if w.gen > 0 {
+		f, err := os.Open(w.path("snapshot", w.gen))
+		if err != nil {
+			return err
+		}
+
+		_, err = w.om.ReadFrom(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("loading snapshot: %w", err)
+		}
+	}
+
+	log, err := os.OpenFile(w.path("log", w.gen), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+
+	valid, err := w.replay(log)
+	if err == nil {
+		err = syncDir(w.dir)
+	}
+
+	if err == nil {
+		err = log.Truncate(valid)
+	}
+
+	if err == nil {
+		_, err = log.Seek(valid, io.SeekStart)
+	}
+
+	if err != nil {
+		log.Close()
+		return err
+	}
+
+	w.log = log
+	return nil
+}
+
+// replay applies every complete record in log to the OrdMap and returns the offset just past the last one. Only the
+// final record may be cut short or damaged, since a crash can't tear any other; a bad checksum anywhere else, or a
+// bad length anywhere at all, is an error.
+func (w *WAL[K, V]) replay(log *os.File) (int64, error) {
+	info, err := log.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	keyCodec, valueCodec := w.om.opts.codecs()
+	cr := &countingReader{r: bufio.NewReader(log)}
+	var changes []Change[K, V]
+	var valid int64
+	var payload []byte
+	for {
+		// a record cut short by the end of the log is the torn final one
+		size, err := binary.ReadUvarint(cr)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("replaying log: %w: record at offset %d: %w", ErrInvalidSnapshot, valid, err)
+		}
+
+		var header [4]byte
+		if _, err := io.ReadFull(cr, header[:]); err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+
+		if crc32.ChecksumIEEE(binary.AppendUvarint(nil, size)) != binary.LittleEndian.Uint32(header[:]) {
+			return 0, fmt.Errorf("replaying log: %w: bad length for record at offset %d", ErrInvalidSnapshot, valid)
+		}
+
+		if size > maxFieldSize {
+			return 0, fmt.Errorf("replaying log: %w: record at offset %d is too large", ErrInvalidSnapshot, valid)
+		}
+
+		// the length is known to be intact, so a record running past the end of the log really was cut short
+		if remaining := uint64(info.Size() - cr.n); size+4 > remaining {
+			break
+		}
+
+		if uint64(cap(payload)) < size+4 {
+			payload = make([]byte, size+4)
+		}
+
+		payload = payload[:size+4]
+		if _, err := io.ReadFull(cr, payload); err != nil {
+			return 0, err
+		}
+
+		sum := binary.LittleEndian.Uint32(payload[size:])
+		payload = payload[:size]
+		if crc32.ChecksumIEEE(payload) != sum {
+			if cr.n == info.Size() {
+				break
+			}
+
+			return 0, fmt.Errorf("replaying log: %w: bad checksum for record at offset %d", ErrInvalidSnapshot, valid)
+		}
+
+		change, err := decodeChange(payload, keyCodec, valueCodec)
+		if err != nil {
+			return 0, fmt.Errorf("replaying log: %w", err)
+		}
+
+		changes = append(changes, change)
+		valid = cr.n
+	}
+
+	w.om.Apply(changes)
+	w.records = len(changes)
+	return valid, nil
+}
+
+// clean removes the files of every generation but the current one, along with any unfinished snapshots.
+func (w *WAL[K, V]) clean() error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+
+	keep := map[string]bool{filepath.Base(w.path("log", w.gen)): true}
+	if w.gen > 0 {
+		keep[filepath.Base(w.path("snapshot", w.gen))] = true
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if keep[name] || !(strings.HasPrefix(name, "snapshot-") || strings.HasPrefix(name, "log-")) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(w.dir, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeChange decodes a single log record's payload.
+func decodeChange[K comparable, V any](payload []byte, keyCodec Codec[K], valueCodec Codec[V]) (Change[K, V], error) {
+	var change Change[K, V]
+	if len(payload) == 0 {
+		return change, fmt.Errorf("%w: empty record", ErrInvalidSnapshot)
+	}
+
+	change.Op = Op(payload[0])
+	field, rest, err := splitField(payload[1:])
+	if err != nil {
+		return change, err
+	}
+
+	if change.Key, err = keyCodec.Decode(field); err != nil {
+		return change, fmt.Errorf("decoding key: %w", err)
+	}
+
+	switch change.Op {
+	case OpSet:
+		if field, _, err = splitField(rest); err != nil {
+			return change, err
+		}
+
+		if change.Value, err = valueCodec.Decode(field); err != nil {
+			return change, fmt.Errorf("decoding value: %w", err)
+		}
+	case OpDelete:
+	default:
+		return change, fmt.Errorf("%w: unknown op %d", ErrInvalidSnapshot, change.Op)
+	}
+
+	return change, nil
+}
+
+// appendField appends val to buf, encoded with codec and prefixed by its length.
+func appendField[T any](buf []byte, codec Codec[T], val T) ([]byte, error) {
+	start := len(buf)
+	buf = append(buf, make([]byte, binary.MaxVarintLen64)...)
+	encoded, err := codec.Append(buf, val)
+	if err != nil {
+		return buf[:start], err
+	}
+
+	// the length prefix is only known once the value is encoded, so move the value back to sit right after it
+	field := encoded[start+binary.MaxVarintLen64:]
+	prefix := binary.PutUvarint(encoded[start:], uint64(len(field)))
+	n := copy(encoded[start+prefix:], field)
+	return encoded[:start+prefix+n], nil
+}
+
+// splitField splits a length-prefixed field off the front of data.
+func splitField(data []byte) ([]byte, []byte, error) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < size {
+		return nil, nil, fmt.Errorf("%w: truncated field", ErrInvalidSnapshot)
+	}
+
+	return data[n : n+int(size)], data[n+int(size):], nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package ordmap
+
+// syncDir does nothing, since directories can't be flushed on this platform.
+func syncDir(path string) error {
+	return nil
+}
@@ -0,0 +1,149 @@
+package ordmap_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func openWAL(t *testing.T, dir string, opts ...ordmap.WALOption) (*ordmap.OrdMap[string, int], *ordmap.WAL[string, int]) {
+	t.Helper()
+	om := ordmap.New[string, int](0)
+	w, err := om.OpenWAL(dir, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error opening WAL: %v", err)
+	}
+
+	return &om, w
+}
+
+func Test_WAL(t *testing.T) {
+	dir := t.TempDir()
+	om, w := openWAL(t, dir, ordmap.WithCompactEvery(4))
+	for i := range 10 {
+		if err := w.Set(fmt.Sprintf("key %d", i), i); err != nil {
+			t.Fatalf("unexpected error setting: %v", err)
+		}
+	}
+
+	if err := w.Delete("key 3"); err != nil {
+		t.Fatalf("unexpected error deleting: %v", err)
+	}
+
+	expected := fmt.Sprint(om.Entries())
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	if err := w.Set("key 10", 10); err == nil {
+		t.Fatal("expected writing to a closed WAL to fail")
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Fatalf("expected compaction to leave one snapshot and one log, found %d files", len(files))
+	}
+
+	reopened, w := openWAL(t, dir)
+	defer w.Close()
+	if got := fmt.Sprint(reopened.Entries()); got != expected {
+		t.Fatalf("expected replay to restore %s, got %s", expected, got)
+	}
+}
+
+func Test_WALTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	_, w := openWAL(t, dir)
+	w.Set("a", 1)
+	w.Set("b", 2)
+	w.Close()
+
+	// simulate a crash halfway through writing the last record
+	logs, _ := filepath.Glob(filepath.Join(dir, "log-*"))
+	info, _ := os.Stat(logs[0])
+	os.Truncate(logs[0], info.Size()-2)
+
+	om, w := openWAL(t, dir)
+	if got := fmt.Sprint(om.Entries()); got != "[{a 1}]" {
+		t.Fatalf("expected the torn record to be dropped, got %s", got)
+	}
+
+	w.Set("c", 3)
+	w.Close()
+	om, w = openWAL(t, dir)
+	defer w.Close()
+	if got := fmt.Sprint(om.Entries()); got != "[{a 1} {c 3}]" {
+		t.Fatalf("expected writes after recovery to be kept, got %s", got)
+	}
+}
+
+func Test_WALCorruptRecord(t *testing.T) {
+	dir := t.TempDir()
+	_, w := openWAL(t, dir)
+	w.Set("a", 1)
+	w.Set("b", 2)
+	w.Close()
+
+	// flip a byte in the first record's payload, which a crash can't explain since another record follows it
+	logs, _ := filepath.Glob(filepath.Join(dir, "log-*"))
+	data, _ := os.ReadFile(logs[0])
+	data[6] ^= 0xff
+	os.WriteFile(logs[0], data, 0o644)
+
+	om := ordmap.New[string, int](0)
+	if _, err := om.OpenWAL(dir); !errors.Is(err, ordmap.ErrInvalidSnapshot) {
+		t.Fatalf("expected a corrupt record in the middle of the log to be reported, got %v", err)
+	}
+}
+
+func Test_WALCorruptLength(t *testing.T) {
+	dir := t.TempDir()
+	_, w := openWAL(t, dir)
+	w.Set("a", 1)
+	w.Set("b", 2)
+	w.Close()
+
+	// a damaged length claiming the first record runs past the end of the log must not be taken for a torn tail,
+	// which would truncate the intact record after it
+	logs, _ := filepath.Glob(filepath.Join(dir, "log-*"))
+	data, _ := os.ReadFile(logs[0])
+	data[0] = 0x7f
+	os.WriteFile(logs[0], data, 0o644)
+
+	om := ordmap.New[string, int](0)
+	if _, err := om.OpenWAL(dir); !errors.Is(err, ordmap.ErrInvalidSnapshot) {
+		t.Fatalf("expected a corrupt length to be reported, got %v", err)
+	}
+
+	if after, _ := os.ReadFile(logs[0]); len(after) != len(data) {
+		t.Fatalf("expected the log to be left alone, it went from %d to %d bytes", len(data), len(after))
+	}
+}
+
+func Test_WALCompactionFailure(t *testing.T) {
+	dir := t.TempDir()
+	om, w := openWAL(t, dir, ordmap.WithCompactEvery(2))
+	defer w.Close()
+	w.Set("a", 1)
+
+	// the open log can still be written to, but a new generation can't be created
+	if err := os.RemoveAll(dir); err != nil {
+		t.Skipf("can't remove the WAL directory while it's in use: %v", err)
+	}
+
+	if err := w.Set("b", 2); err != nil {
+		t.Fatalf("expected a failed automatic compaction not to fail the write, got %v", err)
+	}
+
+	if err := w.Compact(); err == nil {
+		t.Fatal("expected Compact to report the failure")
+	}
+
+	if !om.Has("b") {
+		t.Fatal("expected the write to be applied")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package ordmap
+
+import "os"
+
+// syncDir flushes the directory at path, making the files created, renamed, and removed in it durable.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
@@ -0,0 +1,84 @@
+package ordmap
+
+// watchBuffer is how many changes a watcher's channel can hold before further changes are dropped for it.
+const watchBuffer = 64
+
+// watchers tracks the channels created by Watch and WatchAll.
+type watchers[K comparable, V any] struct {
+	byKey map[K]map[chan Change[K, V]]struct{}
+	all   map[chan Change[K, V]]struct{}
+}
+
+// Watch returns a channel that receives every change to key, along with a function that unsubscribes and closes the
+// channel. Changes are sent while the write lock is held, so they arrive in order, but a watcher that falls more than
+// a small buffer behind misses changes rather than holding up writers. Unsubscribing more than once is harmless.
+func (om *OrdMap[K, V]) Watch(key K) (<-chan Change[K, V], func()) {
+	key = om.normalize(key)
+	ch := make(chan Change[K, V], watchBuffer)
+	om.m.Lock()
+	defer om.m.Unlock()
+	if om.watchers.byKey == nil {
+		om.watchers.byKey = make(map[K]map[chan Change[K, V]]struct{})
+	}
+
+	if om.watchers.byKey[key] == nil {
+		om.watchers.byKey[key] = make(map[chan Change[K, V]]struct{})
+	}
+
+	om.watchers.byKey[key][ch] = struct{}{}
+	return ch, func() {
+		om.m.Lock()
+		defer om.m.Unlock()
+		if _, ok := om.watchers.byKey[key][ch]; !ok {
+			return
+		}
+
+		delete(om.watchers.byKey[key], ch)
+		if len(om.watchers.byKey[key]) == 0 {
+			delete(om.watchers.byKey, key)
+		}
+
+		close(ch)
+	}
+}
+
+// WatchAll returns a channel that receives every change to the OrdMap, along with a function that unsubscribes and
+// closes the channel. Delivery works like Watch, and since every change has the next Version, gaps in the versions
+// received show exactly when changes were missed.
+func (om *OrdMap[K, V]) WatchAll() (<-chan Change[K, V], func()) {
+	ch := make(chan Change[K, V], watchBuffer)
+	om.m.Lock()
+	defer om.m.Unlock()
+	if om.watchers.all == nil {
+		om.watchers.all = make(map[chan Change[K, V]]struct{})
+	}
+
+	om.watchers.all[ch] = struct{}{}
+	return ch, func() {
+		om.m.Lock()
+		defer om.m.Unlock()
+		if _, ok := om.watchers.all[ch]; !ok {
+			return
+		}
+
+		delete(om.watchers.all, ch)
+		close(ch)
+	}
+}
+
+// notify sends change to every interested watcher that has room for it. The write lock must be held by the caller.
+func (w *watchers[K, V]) notify(change Change[K, V]) {
+	for ch := range w.all {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+
+	for ch := range w.byKey[change.Key] {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package ordmap_test
+
+import (
+	"testing"
+
+	"github.com/eriktate/go-ordmap"
+)
+
+func Test_Watch(t *testing.T) {
+	om := ordmap.New[string, int](0)
+	keyCh, unwatchKey := om.Watch("a")
+	allCh, unwatchAll := om.WatchAll()
+
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Delete("a")
+
+	for _, expected := range []ordmap.Op{ordmap.OpSet, ordmap.OpDelete} {
+		change := <-keyCh
+		if change.Op != expected || change.Key != "a" {
+			t.Fatalf("expected %s a, got %s %s", expected, change.Op, change.Key)
+		}
+	}
+
+	for version := uint64(1); version <= 3; version++ {
+		if change := <-allCh; change.Version != version {
+			t.Fatalf("expected change %d, got %d", version, change.Version)
+		}
+	}
+
+	unwatchKey()
+	unwatchKey()
+	om.Set("a", 3)
+	if _, ok := <-keyCh; ok {
+		t.Fatal("expected the key watcher's channel to be closed")
+	}
+
+	// a watcher that falls behind misses changes instead of blocking writers
+	for i := range 1000 {
+		om.Set("b", i)
+	}
+
+	received := 0
+	unwatchAll()
+	for range allCh {
+		received++
+	}
+
+	if received == 0 || received >= 1001 {
+		t.Fatalf("expected a bounded number of buffered changes, got %d", received)
+	}
+}